@@ -0,0 +1,82 @@
+package tracekit
+
+import (
+    "strconv"
+    "strings"
+
+    "github.com/clubpay/ronykit"
+    "go.opentelemetry.io/otel/attribute"
+    semconv "go.opentelemetry.io/otel/semconv/v1.11.0"
+)
+
+const (
+    rpcSystem = "ronykit"
+
+    headerUserAgent      = "User-Agent"
+    headerContentLength  = "Content-Length"
+    headerForwardedProto = "X-Forwarded-Proto"
+    defaultScheme        = "http"
+)
+
+var (
+    contractIDKey = attribute.Key("ronykit.contract.id")
+    connIDKey     = attribute.Key("ronykit.conn.id")
+)
+
+// connAttributes returns the semantic convention attributes of the request. REST connections
+// are described by the HTTP conventions, and the other connections by the RPC conventions.
+func connAttributes(ctx *ronykit.Context) []attribute.KeyValue {
+    kvs := []attribute.KeyValue{
+        contractIDKey.String(ctx.ContractID()),
+        connIDKey.Int64(int64(ctx.Conn().ConnID())),
+    }
+
+    rc, ok := ctx.Conn().(ronykit.RESTConn)
+    if !ok {
+        kvs = append(
+            kvs,
+            semconv.RPCSystemKey.String(rpcSystem),
+            semconv.RPCServiceKey.String(ctx.ServiceName()),
+            semconv.RPCMethodKey.String(ctx.Route()),
+        )
+        if ip := ctx.Conn().ClientIP(); ip != "" {
+            kvs = append(kvs, semconv.NetPeerIPKey.String(ip))
+        }
+
+        return kvs
+    }
+
+    scheme := rc.Get(headerForwardedProto)
+    if scheme == "" {
+        scheme = defaultScheme
+    }
+    kvs = append(
+        kvs,
+        semconv.HTTPMethodKey.String(rc.GetMethod()),
+        semconv.HTTPRouteKey.String(restRoute(ctx.Route())),
+        semconv.HTTPTargetKey.String(rc.GetRequestURI()),
+        semconv.HTTPSchemeKey.String(scheme),
+        semconv.HTTPHostKey.String(rc.GetHost()),
+    )
+    if ua := rc.Get(headerUserAgent); ua != "" {
+        kvs = append(kvs, semconv.HTTPUserAgentKey.String(ua))
+    }
+    if ip := rc.ClientIP(); ip != "" {
+        kvs = append(kvs, semconv.HTTPClientIPKey.String(ip))
+    }
+    if n, err := strconv.Atoi(rc.Get(headerContentLength)); err == nil {
+        kvs = append(kvs, semconv.HTTPRequestContentLengthKey.Int(n))
+    }
+
+    return kvs
+}
+
+// restRoute returns the path template of the REST route, which ronykit sets as
+// "{method} {path}".
+func restRoute(route string) string {
+    if idx := strings.IndexByte(route, ' '); idx >= 0 {
+        return route[idx+1:]
+    }
+
+    return route
+}
@@ -0,0 +1,59 @@
+package tracekit_test
+
+import (
+    "net/http"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/middleware/tracekit"
+    "github.com/clubpay/ronycontrib/middleware/tracekit/tracekittest"
+    "go.opentelemetry.io/otel/attribute"
+    semconv "go.opentelemetry.io/otel/semconv/v1.11.0"
+)
+
+func TestRESTAttributes(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    gw := newGateway(tracekit.W3C("users", tracekit.WithTracerProvider(rec.TracerProvider())), okHandler)
+
+    conn := tracekittest.NewRESTConn(
+        http.MethodGet, "/users/42",
+        map[string]string{"X-Forwarded-Proto": "https", "Content-Length": "invalid"},
+    )
+    send(t, gw, conn, restRoute)
+
+    // The route is the template of the path, and the optional attributes are only set if the
+    // headers are valid.
+    span := rec.Span(restRoute)
+    tracekittest.AssertAttributes(
+        t, span,
+        attribute.Int64("ronykit.conn.id", int64(conn.ConnID())),
+        semconv.HTTPRouteKey.String("/users/:id"),
+        semconv.HTTPTargetKey.String("/users/42"),
+        semconv.HTTPSchemeKey.String("https"),
+    )
+    tracekittest.AssertNoAttributes(
+        t, span,
+        semconv.HTTPUserAgentKey,
+        semconv.HTTPClientIPKey,
+        semconv.HTTPRequestContentLengthKey,
+        semconv.HTTPResponseContentLengthKey,
+    )
+}
+
+func TestRPCAttributes(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    gw := newGateway(tracekit.W3C("users", tracekit.WithTracerProvider(rec.TracerProvider())), okHandler)
+
+    conn := tracekittest.NewConn(nil)
+    send(t, gw, conn, rpcRoute)
+
+    span := rec.Span(rpcRoute)
+    tracekittest.AssertAttributes(
+        t, span,
+        attribute.String("ronykit.contract.id", "users.getUser"),
+        attribute.Int64("ronykit.conn.id", int64(conn.ConnID())),
+        semconv.RPCSystemKey.String("ronykit"),
+        semconv.RPCServiceKey.String("users"),
+        semconv.RPCMethodKey.String(rpcRoute),
+    )
+    tracekittest.AssertNoAttributes(t, span, semconv.NetPeerIPKey, semconv.HTTPRouteKey)
+}
@@ -2,9 +2,40 @@ package tracekit
 
 import (
 	"github.com/clubpay/ronykit"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/trace"
 )
 
 func Span(ctx *ronykit.Context) trace.Span {
 	return trace.SpanFromContext(ctx.Context())
 }
+
+// Baggage returns the baggage which is extracted from the incoming headers, including the
+// members which are set by SetBaggageMember.
+func Baggage(ctx *ronykit.Context) baggage.Baggage {
+	return baggage.FromContext(ctx.Context())
+}
+
+// BaggageMember returns the value of the baggage member with the key, or empty string if it
+// does not exist.
+func BaggageMember(ctx *ronykit.Context, key string) string {
+	return Baggage(ctx).Member(key).Value()
+}
+
+// SetBaggageMember sets the member of the baggage in the user context, hence it is propagated
+// to the downstream calls which use the context.
+func SetBaggageMember(ctx *ronykit.Context, key, value string) error {
+	m, err := baggage.NewMember(key, value)
+	if err != nil {
+		return err
+	}
+
+	b, err := Baggage(ctx).SetMember(m)
+	if err != nil {
+		return err
+	}
+
+	ctx.SetUserContext(baggage.ContextWithBaggage(ctx.Context(), b))
+
+	return nil
+}
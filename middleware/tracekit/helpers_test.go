@@ -0,0 +1,56 @@
+package tracekit_test
+
+import (
+    "testing"
+
+    "github.com/clubpay/ronycontrib/middleware/tracekit"
+    "github.com/clubpay/ronycontrib/middleware/tracekit/tracekittest"
+    "github.com/clubpay/ronykit"
+    "go.opentelemetry.io/otel/attribute"
+    semconv "go.opentelemetry.io/otel/semconv/v1.11.0"
+)
+
+func TestBaggage(t *testing.T) {
+    var (
+        tenant, region string
+        members        int
+        setErr         error
+    )
+    rec := tracekittest.NewRecorder()
+    gw := newGateway(
+        tracekit.W3C(
+            "users",
+            tracekit.WithTracerProvider(rec.TracerProvider()),
+            tracekit.ServiceName("users"),
+            tracekit.BaggageAttributes("tenant", "service.name"),
+        ),
+        func(ctx *ronykit.Context) {
+            tenant = tracekit.BaggageMember(ctx, "tenant")
+            setErr = tracekit.SetBaggageMember(ctx, "region", "eu")
+            region = tracekit.BaggageMember(ctx, "region")
+            members = tracekit.Baggage(ctx).Len()
+            if !tracekit.Span(ctx).SpanContext().IsValid() {
+                t.Errorf("span is not set in the user context")
+            }
+            okHandler(ctx)
+        },
+    )
+
+    hdr := map[string]string{"baggage": "tenant=acme,service.name=spoofed"}
+    send(t, gw, tracekittest.NewConn(hdr), rpcRoute)
+
+    if setErr != nil {
+        t.Fatal(setErr)
+    }
+    if tenant != "acme" || region != "eu" || members != 3 {
+        t.Errorf("tenant = %q, region = %q, members = %d", tenant, region, members)
+    }
+
+    // Baggage members are prefixed, so they do not overwrite the attributes of the handler.
+    tracekittest.AssertAttributes(
+        t, rec.Span(rpcRoute),
+        semconv.ServiceNameKey.String("users"),
+        attribute.String("baggage.tenant", "acme"),
+        attribute.String("baggage.service.name", "spoofed"),
+    )
+}
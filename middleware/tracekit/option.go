@@ -1,16 +1,70 @@
 package tracekit
 
-import "github.com/clubpay/ronykit"
+import (
+	"net/http"
+
+	"github.com/clubpay/ronykit"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.11.0"
+	"go.opentelemetry.io/otel/trace"
+)
 
 type Option func(cfg *config)
 
 type config struct {
 	tracerName  string
-	propagator  TracePropagator
+	provider    trace.TracerProvider
+	propagator  propagation.TextMapPropagator
+	injector    propagation.TextMapPropagator
 	serviceName string
 	env         string
 	tags        map[string]string
 	dynTags     func(ctx *ronykit.LimitedContext) map[string]string
+	inject      bool
+	b3Encoding  b3.Encoding
+	baggageKeys []string
+	clientErrs  bool
+	resSize     bool
+	spanName    func(ctx *ronykit.LimitedContext) string
+	sampler     sampler
+}
+
+func newConfig(name string, opts ...Option) *config {
+	cfg := &config{
+		tracerName: name,
+		inject:     true,
+		b3Encoding: b3.B3SingleHeader,
+		spanName:   defaultSpanName,
+		sampler:    sampler{ratio: 1},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// ResourceAttributes returns the attributes which are set by ServiceName, Env and WithTags, so
+// the resource of a tracer provider could be described by the options of the handler.
+func ResourceAttributes(opts ...Option) []attribute.KeyValue {
+	return newConfig("", opts...).attributes()
+}
+
+func (cfg *config) attributes() []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(cfg.tags)+2)
+	for k, v := range cfg.tags {
+		kvs = append(kvs, attribute.String(k, v))
+	}
+	if cfg.serviceName != "" {
+		kvs = append(kvs, semconv.ServiceNameKey.String(cfg.serviceName))
+	}
+	if cfg.env != "" {
+		kvs = append(kvs, semconv.DeploymentEnvironmentKey.String(cfg.env))
+	}
+
+	return kvs
 }
 
 func ServiceName(name string) Option {
@@ -36,3 +90,154 @@ func WithDynamicTags(f func(ctx *ronykit.LimitedContext) map[string]string) Opti
 		cfg.dynTags = f
 	}
 }
+
+// Inject sets if the span context is injected into the headers of the outgoing envelopes,
+// i.e. the REST response headers and the envelopes which are sent to RPC connections.
+// It is enabled by default, and the headers are set by the propagator of WithInjectPropagator.
+func Inject(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.inject = enabled
+	}
+}
+
+// WithInjectPropagator sets the propagator which injects the span context into the outgoing
+// headers. By default, the propagator of the handler is used for both extraction and injection.
+// For example, b3 headers could be accepted from the upstream services while only traceparent
+// is sent to the downstream ones:
+//
+//	tracekit.B3("name", tracekit.WithInjectPropagator(propagation.TraceContext{}))
+func WithInjectPropagator(p propagation.TextMapPropagator) Option {
+	return func(cfg *config) {
+		cfg.injector = p
+	}
+}
+
+// B3Encoding sets the encoding of the injected b3 headers. By default, the single b3 header
+// is injected. It has no effect on the W3C propagator.
+func B3Encoding(enc b3.Encoding) Option {
+	return func(cfg *config) {
+		cfg.b3Encoding = enc
+	}
+}
+
+// WithPropagator overrides the propagator of the tracing handler. It could be any propagator,
+// including composites, e.g. to accept both b3 and traceparent headers with baggage:
+//
+//	tracekit.W3C(
+//		"name",
+//		tracekit.WithPropagator(
+//			propagation.NewCompositeTextMapPropagator(
+//				b3.New(), propagation.TraceContext{}, propagation.Baggage{},
+//			),
+//		),
+//	)
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(cfg *config) {
+		cfg.propagator = p
+	}
+}
+
+// WithTracerProvider sets the provider of the tracer of the handler. By default, the tracer is
+// looked up from the global provider for each request, so the provider which is set by
+// otel.SetTracerProvider after the handler is created is used as well.
+//
+// If the provider is a no-op provider, e.g. trace.NewNoopTracerProvider(), no span is recorded,
+// but the upstream span context is still extracted and propagated unchanged to the user context
+// and the injected headers, so the trace is not broken by the service.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(cfg *config) {
+		cfg.provider = tp
+	}
+}
+
+// BaggageAttributes promotes the baggage members with the given keys to the attributes of
+// the span. The attributes are prefixed by "baggage.", e.g. baggage.tenant, so the members
+// which are set by the clients could not overwrite the other attributes.
+func BaggageAttributes(keys ...string) Option {
+	return func(cfg *config) {
+		cfg.baggageKeys = append(cfg.baggageKeys, keys...)
+	}
+}
+
+// ClientErrors sets if the 4xx status codes, or error messages with 4xx codes, set the status
+// of the span to Error. By default, only 5xx codes are errors.
+func ClientErrors(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.clientErrs = enabled
+	}
+}
+
+func (cfg *config) isErrorCode(code int) bool {
+	switch {
+	case code >= http.StatusInternalServerError:
+		return true
+	case code >= http.StatusBadRequest:
+		return cfg.clientErrs
+	default:
+		return false
+	}
+}
+
+// ResponseContentLength sets if the http.response_content_length attribute is set for REST
+// requests. Since the size is not known before the response is written, the outgoing messages
+// are marshaled once more to measure it, hence it is disabled by default.
+func ResponseContentLength(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.resSize = enabled
+	}
+}
+
+// WithSpanName sets the formatter of the span names. By default, spans are named by the route
+// of the context, e.g. "GET /users/:id" for REST requests, or the predicate for RPC requests.
+// For example, RPC spans could be grouped by their service:
+//
+//	tracekit.WithSpanName(
+//		func(ctx *ronykit.LimitedContext) string {
+//			return fmt.Sprintf("%s.%s", ctx.ServiceName(), ctx.Route())
+//		},
+//	)
+func WithSpanName(f func(ctx *ronykit.LimitedContext) string) Option {
+	return func(cfg *config) {
+		cfg.spanName = f
+	}
+}
+
+func defaultSpanName(ctx *ronykit.LimitedContext) string {
+	return ctx.Route()
+}
+
+// SkipRoutes disables tracing of the routes which match any of the patterns, e.g. health checks
+// and metrics. Patterns have the syntax of path.Match, and REST routes are matched by both
+// their "{method} {path}" form and their path, e.g. "/health" or "GET /metrics*".
+func SkipRoutes(patterns ...string) Option {
+	return func(cfg *config) {
+		cfg.sampler.skip = append(cfg.sampler.skip, patterns...)
+	}
+}
+
+// SampleRatio sets the ratio of the requests which are sampled, between 0 and 1, if the route
+// matches none of the SampleRoute rules. It is 1 by default. Requests which have an upstream
+// span context are sampled only if the upstream span is sampled.
+func SampleRatio(ratio float64) Option {
+	return func(cfg *config) {
+		cfg.sampler.ratio = ratio
+	}
+}
+
+// SampleRoute sets the sampling ratio of the routes which match the pattern. The pattern has
+// the syntax of SkipRoutes, and the first matching rule is used.
+func SampleRoute(pattern string, ratio float64) Option {
+	return func(cfg *config) {
+		cfg.sampler.rules = append(cfg.sampler.rules, sampleRule{pattern: pattern, ratio: ratio})
+	}
+}
+
+// SampleOnError sets if the requests which are not sampled, are still traced when they fail.
+// The decision is deferred until the handler returns, then the span is started with the start
+// time of the request and linked to the upstream span. The span context which is propagated
+// to downstream services is not sampled, hence their spans are not recorded.
+func SampleOnError(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.sampler.sampleOnError = enabled
+	}
+}
@@ -0,0 +1,197 @@
+package tracekit_test
+
+import (
+    "context"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/middleware/tracekit"
+    "github.com/clubpay/ronycontrib/middleware/tracekit/tracekittest"
+    "github.com/clubpay/ronykit"
+    "go.opentelemetry.io/contrib/propagators/aws/xray"
+    "go.opentelemetry.io/contrib/propagators/b3"
+    "go.opentelemetry.io/contrib/propagators/jaeger"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/trace"
+)
+
+func TestPropagators(t *testing.T) {
+    for _, tc := range []struct {
+        name       string
+        handler    func(opts ...tracekit.Option) ronykit.HandlerFunc
+        propagator propagation.TextMapPropagator
+        header     string
+    }{
+        {
+            name:       "B3Single",
+            handler:    func(opts ...tracekit.Option) ronykit.HandlerFunc { return tracekit.B3("users", opts...) },
+            propagator: b3.New(),
+            header:     "b3",
+        },
+        {
+            name: "B3Multi",
+            handler: func(opts ...tracekit.Option) ronykit.HandlerFunc {
+                return tracekit.B3("users", append(opts, tracekit.B3Encoding(b3.B3MultipleHeader))...)
+            },
+            propagator: b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)),
+            header:     "x-b3-traceid",
+        },
+        {
+            name:       "W3C",
+            handler:    func(opts ...tracekit.Option) ronykit.HandlerFunc { return tracekit.W3C("users", opts...) },
+            propagator: propagation.TraceContext{},
+            header:     "traceparent",
+        },
+        {
+            name:       "Jaeger",
+            handler:    func(opts ...tracekit.Option) ronykit.HandlerFunc { return tracekit.Jaeger("users", opts...) },
+            propagator: jaeger.Jaeger{},
+            header:     "uber-trace-id",
+        },
+        {
+            name:       "XRay",
+            handler:    func(opts ...tracekit.Option) ronykit.HandlerFunc { return tracekit.XRay("users", opts...) },
+            propagator: xray.Propagator{},
+            header:     "X-Amzn-Trace-Id",
+        },
+    } {
+        t.Run(tc.name, func(t *testing.T) {
+            rec := tracekittest.NewRecorder()
+            gw := newGateway(tc.handler(tracekit.WithTracerProvider(rec.TracerProvider())), okHandler)
+
+            parent := parentSpanContext(true)
+            hdr := tracekittest.Headers(tc.propagator, parent)
+            if len(hdr) == 0 {
+                t.Fatal("propagator injects no header")
+            }
+            out := send(t, gw, tracekittest.NewConn(hdr), rpcRoute)
+
+            span := rec.Span(rpcRoute)
+            if span == nil {
+                t.Fatal("span is not recorded")
+            }
+            tracekittest.AssertChildOf(t, span, parent)
+            if _, ok := out[0].Hdr[tc.header]; !ok {
+                t.Errorf("header %s is not injected: %v", tc.header, out[0].Hdr)
+            }
+            tracekittest.AssertPropagated(t, tc.propagator, out[0].Hdr, span)
+        })
+    }
+}
+
+func TestJaegerShortIDs(t *testing.T) {
+    // Jaeger clients drop the leading zeros of the ids.
+    ctx := tracekit.JaegerPropagator().Extract(
+        context.Background(), propagation.MapCarrier{"uber-trace-id": "abc:def:0:1"},
+    )
+
+    sc := trace.SpanContextFromContext(ctx)
+    if got := sc.TraceID().String(); got != "00000000000000000000000000000abc" {
+        t.Errorf("trace id = %s", got)
+    }
+    if got := sc.SpanID().String(); got != "0000000000000def" {
+        t.Errorf("span id = %s", got)
+    }
+    if !sc.IsSampled() || !sc.IsRemote() {
+        t.Errorf("span context must be sampled and remote")
+    }
+}
+
+func TestJaegerExtract(t *testing.T) {
+    for _, tc := range []struct {
+        name    string
+        header  string
+        valid   bool
+        sampled bool
+    }{
+        {name: "Sampled", header: "0102030405060708090a0b0c0d0e0f10:0102030405060708:0:1", valid: true, sampled: true},
+        {name: "NotSampled", header: "0102030405060708090a0b0c0d0e0f10:0102030405060708:0:0", valid: true},
+        {name: "Debug", header: "0102030405060708090a0b0c0d0e0f10:0102030405060708:0:3", valid: true, sampled: true},
+        {name: "MissingParts", header: "0102030405060708090a0b0c0d0e0f10:0102030405060708:1"},
+        {name: "InvalidTraceID", header: "xyz:0102030405060708:0:1"},
+        {name: "InvalidFlags", header: "0102030405060708090a0b0c0d0e0f10:0102030405060708:0:x"},
+        {name: "ZeroIDs", header: "0:0:0:1"},
+    } {
+        t.Run(tc.name, func(t *testing.T) {
+            sc := trace.SpanContextFromContext(
+                tracekit.JaegerPropagator().Extract(
+                    context.Background(), propagation.MapCarrier{"uber-trace-id": tc.header},
+                ),
+            )
+            if sc.IsValid() != tc.valid {
+                t.Fatalf("valid = %t, want %t", sc.IsValid(), tc.valid)
+            }
+            if sc.IsSampled() != tc.sampled {
+                t.Errorf("sampled = %t, want %t", sc.IsSampled(), tc.sampled)
+            }
+        })
+    }
+}
+
+func TestXRayExtract(t *testing.T) {
+    for _, tc := range []struct {
+        name    string
+        header  string
+        valid   bool
+        sampled bool
+    }{
+        {
+            name:    "Sampled",
+            header:  "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1",
+            valid:   true,
+            sampled: true,
+        },
+        {
+            name:   "NotSampled",
+            header: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=0",
+            valid:  true,
+        },
+        {
+            name:   "ExtraFields",
+            header: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Lineage=a87bd80c:1",
+            valid:  true,
+        },
+        {name: "MissingParent", header: "Root=1-5759e988-bd862e3fe1be46a994272793;Sampled=1"},
+        {name: "InvalidVersion", header: "Root=2-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8"},
+        {name: "InvalidParent", header: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=xyz"},
+    } {
+        t.Run(tc.name, func(t *testing.T) {
+            sc := trace.SpanContextFromContext(
+                tracekit.XRayPropagator().Extract(
+                    context.Background(), propagation.MapCarrier{"X-Amzn-Trace-Id": tc.header},
+                ),
+            )
+            if sc.IsValid() != tc.valid {
+                t.Fatalf("valid = %t, want %t", sc.IsValid(), tc.valid)
+            }
+            if sc.IsSampled() != tc.sampled {
+                t.Errorf("sampled = %t, want %t", sc.IsSampled(), tc.sampled)
+            }
+            if tc.valid && sc.TraceID().String() != "5759e988bd862e3fe1be46a994272793" {
+                t.Errorf("trace id = %s", sc.TraceID())
+            }
+        })
+    }
+}
+
+func TestCompositePropagator(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    composite := propagation.NewCompositeTextMapPropagator(
+        b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)), propagation.TraceContext{}, propagation.Baggage{},
+    )
+    gw := newGateway(
+        tracekit.W3C("users", tracekit.WithTracerProvider(rec.TracerProvider()), tracekit.WithPropagator(composite)),
+        okHandler,
+    )
+
+    // The upstream service sends b3 multi headers, and the response carries all of the headers.
+    parent := parentSpanContext(true)
+    hdr := tracekittest.Headers(b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)), parent)
+    hdr["baggage"] = "tenant=acme"
+    out := send(t, gw, tracekittest.NewConn(hdr), rpcRoute)
+
+    span := rec.Span(rpcRoute)
+    tracekittest.AssertChildOf(t, span, parent)
+    tracekittest.AssertPropagated(t, propagation.TraceContext{}, out[0].Hdr, span)
+    tracekittest.AssertPropagated(t, b3.New(), out[0].Hdr, span)
+    tracekittest.AssertHeader(t, out[0].Hdr, "baggage", "tenant=acme")
+}
@@ -0,0 +1,91 @@
+package tracekit
+
+import (
+    "crypto/rand"
+    mathrand "math/rand"
+    "path"
+
+    "go.opentelemetry.io/otel/trace"
+)
+
+// sampleRule sets the sampling ratio of the routes which match the pattern.
+type sampleRule struct {
+    pattern string
+    ratio   float64
+}
+
+// sampler makes the head sampling decisions of the middleware, before the SDK sampler. Requests
+// which are not sampled by it, never start a span unless sampleOnError is set and the request
+// fails.
+type sampler struct {
+    skip          []string
+    rules         []sampleRule
+    ratio         float64
+    sampleOnError bool
+}
+
+// skipped reports if the route must not be traced at all.
+func (s *sampler) skipped(route string) bool {
+    for _, p := range s.skip {
+        if matchRoute(p, route) {
+            return true
+        }
+    }
+
+    return false
+}
+
+// sample reports if the request should be traced. The sampled flag of the upstream span is
+// respected, otherwise the ratio of the first matching rule, or the default ratio is used.
+func (s *sampler) sample(route string, parent trace.SpanContext) bool {
+    if parent.IsValid() {
+        return parent.IsSampled()
+    }
+
+    ratio := s.ratio
+    for _, r := range s.rules {
+        if matchRoute(r.pattern, route) {
+            ratio = r.ratio
+
+            break
+        }
+    }
+
+    switch {
+    case ratio >= 1:
+        return true
+    case ratio <= 0:
+        return false
+    default:
+        return mathrand.Float64() < ratio
+    }
+}
+
+// matchRoute reports if the route matches the pattern, which has the syntax of path.Match.
+// REST routes are matched by both their "{method} {path}" form and their path template, e.g.
+// both "GET /health" and "/health" match the health route.
+func matchRoute(pattern, route string) bool {
+    if ok, _ := path.Match(pattern, route); ok {
+        return true
+    }
+    if ok, _ := path.Match(pattern, restRoute(route)); ok {
+        return true
+    }
+
+    return false
+}
+
+// unsampledSpanContext returns a span context which is not sampled, so the downstream services
+// do not sample the trace either. It continues the trace of the parent if it is valid.
+func unsampledSpanContext(parent trace.SpanContext) trace.SpanContext {
+    scc := trace.SpanContextConfig{
+        TraceID:    parent.TraceID(),
+        TraceState: parent.TraceState(),
+    }
+    if !scc.TraceID.IsValid() {
+        _, _ = rand.Read(scc.TraceID[:])
+    }
+    _, _ = rand.Read(scc.SpanID[:])
+
+    return trace.NewSpanContext(scc)
+}
@@ -0,0 +1,218 @@
+package tracekit_test
+
+import (
+    "context"
+    "net/http"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/middleware/tracekit"
+    "github.com/clubpay/ronycontrib/middleware/tracekit/tracekittest"
+    "github.com/clubpay/ronykit"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/trace"
+)
+
+func TestSampling(t *testing.T) {
+    for _, tc := range []struct {
+        name    string
+        opts    []tracekit.Option
+        parent  *trace.SpanContext
+        sampled bool
+    }{
+        {name: "Default", sampled: true},
+        {name: "RatioZero", opts: []tracekit.Option{tracekit.SampleRatio(0)}, sampled: false},
+        {
+            name:    "RouteRule",
+            opts:    []tracekit.Option{tracekit.SampleRoute("/users/*", 1), tracekit.SampleRatio(0)},
+            sampled: true,
+        },
+        {
+            name:    "FirstRuleWins",
+            opts:    []tracekit.Option{tracekit.SampleRoute("GET /users/*", 0), tracekit.SampleRoute("/users/*", 1)},
+            sampled: false,
+        },
+        {
+            name:    "UpstreamSampled",
+            opts:    []tracekit.Option{tracekit.SampleRatio(0)},
+            parent:  spanContextPtr(parentSpanContext(true)),
+            sampled: true,
+        },
+        {
+            name:    "UpstreamNotSampled",
+            parent:  spanContextPtr(parentSpanContext(false)),
+            sampled: false,
+        },
+    } {
+        t.Run(tc.name, func(t *testing.T) {
+            rec := tracekittest.NewRecorder()
+            opts := append([]tracekit.Option{tracekit.WithTracerProvider(rec.TracerProvider())}, tc.opts...)
+            gw := newGateway(tracekit.W3C("users", opts...), okHandler)
+
+            var hdr map[string]string
+            if tc.parent != nil {
+                hdr = tracekittest.Headers(propagation.TraceContext{}, *tc.parent)
+            }
+            out := send(t, gw, tracekittest.NewRESTConn(http.MethodGet, "/users/1", hdr), restRoute)
+
+            if got := len(rec.Spans()) == 1; got != tc.sampled {
+                t.Fatalf("sampled = %t, want %t", got, tc.sampled)
+            }
+
+            // The decision is propagated to the downstream services.
+            sc := extract(out[0].Hdr)
+            if !sc.IsValid() {
+                t.Fatalf("no span context is injected: %v", out[0].Hdr)
+            }
+            if sc.IsSampled() != tc.sampled {
+                t.Errorf("injected sampled = %t, want %t", sc.IsSampled(), tc.sampled)
+            }
+            if tc.parent != nil && sc.TraceID() != tc.parent.TraceID() {
+                t.Errorf("injected trace id = %s, want %s", sc.TraceID(), tc.parent.TraceID())
+            }
+        })
+    }
+}
+
+func TestSkipRoutes(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    gw := newGateway(
+        tracekit.W3C(
+            "users",
+            tracekit.WithTracerProvider(rec.TracerProvider()),
+            tracekit.SkipRoutes("/health", "/users/*"),
+        ),
+        okHandler,
+    )
+
+    out := send(t, gw, tracekittest.NewRESTConn(http.MethodGet, "/users/1", nil), restRoute)
+    tracekittest.AssertSpanNames(t, rec.Spans())
+    if _, ok := out[0].Hdr["traceparent"]; ok {
+        t.Errorf("traceparent is injected for the skipped route")
+    }
+
+    send(t, gw, tracekittest.NewConn(nil), rpcRoute)
+    tracekittest.AssertSpanNames(t, rec.Spans(), rpcRoute)
+}
+
+func TestSampleOnError(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    parent := parentSpanContext(false)
+    hdr := tracekittest.Headers(propagation.TraceContext{}, parent)
+
+    gw := newGateway(
+        tracekit.W3C(
+            "users",
+            tracekit.WithTracerProvider(rec.TracerProvider()),
+            tracekit.SampleOnError(true),
+        ),
+        errorHandler(http.StatusInternalServerError),
+    )
+    send(t, gw, tracekittest.NewRESTConn(http.MethodGet, "/users/1", hdr), restRoute)
+
+    tracekittest.AssertSpanNames(t, rec.Spans(), restRoute)
+    span := rec.Span(restRoute)
+    tracekittest.AssertStatus(t, span, codes.Error)
+    if span.Parent().IsValid() {
+        t.Errorf("deferred span must be a root span, parent = %s", span.Parent().SpanID())
+    }
+    if len(span.Links()) != 1 || span.Links()[0].SpanContext.SpanID() != parent.SpanID() {
+        t.Errorf("deferred span must be linked to the upstream span, links = %v", span.Links())
+    }
+
+    // Successful requests are not sampled.
+    rec.Reset()
+    gw = newGateway(
+        tracekit.W3C(
+            "users",
+            tracekit.WithTracerProvider(rec.TracerProvider()),
+            tracekit.SampleOnError(true),
+        ),
+        okHandler,
+    )
+    send(t, gw, tracekittest.NewRESTConn(http.MethodGet, "/users/1", hdr), restRoute)
+    tracekittest.AssertSpanNames(t, rec.Spans())
+}
+
+func TestSampleRules(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    gw := newGateway(
+        tracekit.W3C(
+            "users",
+            tracekit.WithTracerProvider(rec.TracerProvider()),
+            tracekit.SkipRoutes("GET /users/*"),
+            tracekit.SampleRoute("get*", 0),
+        ),
+        okHandler,
+    )
+
+    // REST routes are matched by their method, and RPC routes by their predicate.
+    send(t, gw, tracekittest.NewRESTConn(http.MethodGet, "/users/1", nil), restRoute)
+    out := send(t, gw, tracekittest.NewConn(nil), rpcRoute)
+    tracekittest.AssertSpanNames(t, rec.Spans())
+    if sc := extract(out[0].Hdr); !sc.IsValid() || sc.IsSampled() {
+        t.Errorf("unsampled span context is not injected: %v", out[0].Hdr)
+    }
+}
+
+func TestSampleRatio(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    gw := newGateway(
+        tracekit.W3C("users", tracekit.WithTracerProvider(rec.TracerProvider()), tracekit.SampleRatio(0.5)),
+        okHandler,
+    )
+
+    const n = 400
+    for i := 0; i < n; i++ {
+        send(t, gw, tracekittest.NewConn(nil), rpcRoute)
+    }
+    if sampled := len(rec.Spans()); sampled < n/4 || sampled > n*3/4 {
+        t.Errorf("sampled %d of %d requests with ratio 0.5", sampled, n)
+    }
+}
+
+func TestSampleOnErrorPanic(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    gw := newGateway(
+        tracekit.W3C(
+            "users",
+            tracekit.WithTracerProvider(rec.TracerProvider()),
+            tracekit.SampleRatio(0),
+            tracekit.SampleOnError(true),
+        ),
+        func(ctx *ronykit.Context) {
+            panic("boom")
+        },
+    )
+
+    func() {
+        defer func() {
+            if r := recover(); r != "boom" {
+                t.Errorf("recovered %v, want the panic of the handler", r)
+            }
+        }()
+
+        _, _ = gw.Send(tracekittest.NewConn(nil), rpcRoute, nil)
+    }()
+
+    // There is no upstream span, hence the deferred span is a root span without links.
+    tracekittest.AssertSpanNames(t, rec.Spans(), rpcRoute)
+    span := rec.Span(rpcRoute)
+    tracekittest.AssertStatus(t, span, codes.Error)
+    if span.Parent().IsValid() || len(span.Links()) != 0 {
+        t.Errorf("deferred span must be a root span without links: %v", span.Links())
+    }
+    if span.SpanKind() != trace.SpanKindServer {
+        t.Errorf("span kind = %s, want %s", span.SpanKind(), trace.SpanKindServer)
+    }
+}
+
+func spanContextPtr(sc trace.SpanContext) *trace.SpanContext {
+    return &sc
+}
+
+func extract(hdr map[string]string) trace.SpanContext {
+    return trace.SpanContextFromContext(
+        propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier(hdr)),
+    )
+}
@@ -0,0 +1,38 @@
+package tkprovider
+
+import (
+    "context"
+
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+    "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+    "go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// OTLPExporter returns an exporter which sends the spans to an OTLP collector over gRPC. By
+// default, it connects to localhost:4317, and the OTEL_EXPORTER_OTLP_* environment variables
+// are respected.
+func OTLPExporter(ctx context.Context, opts ...otlptracegrpc.Option) (*otlptrace.Exporter, error) {
+    return otlptracegrpc.New(ctx, opts...)
+}
+
+// OTLPHTTPExporter returns an exporter which sends the spans to an OTLP collector over HTTP. By
+// default, it sends them to https://localhost:4318/v1/traces, and the OTEL_EXPORTER_OTLP_*
+// environment variables are respected.
+func OTLPHTTPExporter(ctx context.Context, opts ...otlptracehttp.Option) (*otlptrace.Exporter, error) {
+    return otlptracehttp.New(ctx, opts...)
+}
+
+// InMemoryExporter returns an exporter which keeps the spans in memory, to be used in tests.
+// Since the spans are batched, the provider must be flushed by ForceFlush before reading them,
+// and the spans are removed when the provider is shut down.
+func InMemoryExporter() *tracetest.InMemoryExporter {
+    return tracetest.NewInMemoryExporter()
+}
+
+// StdoutExporter returns an exporter which writes the spans to os.Stdout as JSON, one span per
+// line. The writer could be set by stdouttrace.WithWriter.
+func StdoutExporter(opts ...stdouttrace.Option) (*stdouttrace.Exporter, error) {
+    return stdouttrace.New(opts...)
+}
@@ -0,0 +1,90 @@
+package tkprovider_test
+
+import (
+    "bytes"
+    "context"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/middleware/tracekit/tkprovider"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+    "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+)
+
+func TestStdoutExporter(t *testing.T) {
+    buf := &bytes.Buffer{}
+    exp, err := tkprovider.StdoutExporter(stdouttrace.WithWriter(buf))
+    if err != nil {
+        t.Fatal(err)
+    }
+    tp, err := tkprovider.NewTracerProvider(exp)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    gw := newGateway(tp)
+    send(t, gw)
+    send(t, gw)
+    if err = tp.Shutdown(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+
+    lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+    if len(lines) != 2 {
+        t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+    }
+    if !strings.Contains(lines[0], `"Name":"ping"`) {
+        t.Errorf("span is not written: %s", lines[0])
+    }
+}
+
+func TestOTLPHTTPExporter(t *testing.T) {
+    var (
+        mtx      sync.Mutex
+        requests []string
+    )
+    collector := httptest.NewServer(
+        http.HandlerFunc(
+            func(w http.ResponseWriter, r *http.Request) {
+                _, _ = io.Copy(io.Discard, r.Body)
+
+                mtx.Lock()
+                requests = append(requests, r.Method+" "+r.URL.Path)
+                mtx.Unlock()
+
+                w.Header().Set("Content-Type", "application/x-protobuf")
+                w.WriteHeader(http.StatusOK)
+            },
+        ),
+    )
+    defer collector.Close()
+
+    ctx := context.Background()
+    exp, err := tkprovider.OTLPHTTPExporter(
+        ctx,
+        otlptracehttp.WithEndpoint(strings.TrimPrefix(collector.URL, "http://")),
+        otlptracehttp.WithInsecure(),
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+    tp, err := tkprovider.NewTracerProvider(exp)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    send(t, newGateway(tp))
+    if err = tp.Shutdown(ctx); err != nil {
+        t.Fatal(err)
+    }
+
+    mtx.Lock()
+    defer mtx.Unlock()
+    if len(requests) != 1 || requests[0] != "POST /v1/traces" {
+        t.Errorf("collector requests = %v, want one POST /v1/traces", requests)
+    }
+}
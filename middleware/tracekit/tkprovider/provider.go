@@ -0,0 +1,62 @@
+// Package tkprovider bootstraps the OpenTelemetry SDK for the tracing handlers of tracekit. It
+// is a separate module, so the services which set up their own provider do not depend on the
+// SDK and the exporters, nor on their gRPC and protobuf dependencies.
+package tkprovider
+
+import (
+    "context"
+    "os"
+
+    "github.com/clubpay/ronycontrib/middleware/tracekit"
+    "github.com/clubpay/ronykit"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewTracerProvider returns an SDK provider which batches the spans, and exports them with exp.
+// The resource of the provider has the attributes of tracekit.ServiceName, tracekit.Env and
+// tracekit.WithTags, in addition to the default resource of the SDK, so the options could be
+// shared with the handler:
+//
+//	exp, err := tkprovider.OTLPExporter(ctx, otlptracegrpc.WithEndpoint("collector:4317"))
+//	if err != nil {
+//		return err
+//	}
+//
+//	opts := []tracekit.Option{tracekit.ServiceName("users"), tracekit.Env("prod")}
+//	tp, err := tkprovider.NewTracerProvider(exp, opts...)
+//	if err != nil {
+//		return err
+//	}
+//
+//	srv := ronykit.NewServer(
+//		ronykit.WithGlobalHandlers(
+//			tracekit.W3C("users", append(opts, tracekit.WithTracerProvider(tp))...),
+//		),
+//	)
+//
+// StdoutExporter and InMemoryExporter could be used for local development and tests.
+func NewTracerProvider(exp sdktrace.SpanExporter, opts ...tracekit.Option) (*sdktrace.TracerProvider, error) {
+    res, err := resource.Merge(
+        resource.Default(),
+        resource.NewSchemaless(tracekit.ResourceAttributes(opts...)...),
+    )
+    if err != nil {
+        return nil, err
+    }
+
+    return sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(exp),
+        sdktrace.WithResource(res),
+    ), nil
+}
+
+// Shutdown waits for the signals and shuts down the server, then it shuts down the provider, so
+// the spans of the requests which are served during the shutdown are exported too.
+func Shutdown(
+        ctx context.Context, srv *ronykit.EdgeServer, tp *sdktrace.TracerProvider, signals ...os.Signal,
+) error {
+    srv.Shutdown(ctx, signals...)
+
+    return tp.Shutdown(ctx)
+}
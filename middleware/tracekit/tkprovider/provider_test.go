@@ -0,0 +1,96 @@
+package tkprovider_test
+
+import (
+    "context"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/middleware/tracekit"
+    "github.com/clubpay/ronycontrib/middleware/tracekit/tkprovider"
+    "github.com/clubpay/ronycontrib/middleware/tracekit/tracekittest"
+    "github.com/clubpay/ronykit"
+    "github.com/clubpay/ronykit/desc"
+    "github.com/clubpay/ronykit/std/gateway/fasthttp"
+    "go.opentelemetry.io/otel/attribute"
+    semconv "go.opentelemetry.io/otel/semconv/v1.11.0"
+    "go.opentelemetry.io/otel/trace"
+)
+
+const route = "ping"
+
+type pingMsg struct {
+    ID string `json:"id"`
+}
+
+// newGateway serves the ping contract with the tracing handler of the provider.
+func newGateway(tp trace.TracerProvider) *tracekittest.Gateway {
+    svc := desc.NewService("ping").
+        AddContract(
+            desc.NewContract().
+                SetName("ping").
+                SetInput(&pingMsg{}).
+                SetOutput(&pingMsg{}).
+                AddSelector(fasthttp.RPC(route)).
+                SetHandler(
+                    func(ctx *ronykit.Context) {
+                        ctx.Out().SetMsg(&pingMsg{ID: "1"}).Send()
+                    },
+                ),
+        )
+
+    return tracekittest.NewGateway(
+        ronykit.WithGlobalHandlers(tracekit.W3C("ping", tracekit.WithTracerProvider(tp))),
+    ).Serve(svc.Generate())
+}
+
+func send(t *testing.T, gw *tracekittest.Gateway) {
+    t.Helper()
+
+    if _, err := gw.Send(tracekittest.NewConn(nil), route, nil); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestNewTracerProvider(t *testing.T) {
+    exp := tkprovider.InMemoryExporter()
+    tp, err := tkprovider.NewTracerProvider(
+        exp,
+        tracekit.ServiceName("ping"),
+        tracekit.Env("test"),
+        tracekit.WithTags(map[string]string{"team": "identity"}),
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    gw := newGateway(tp)
+    send(t, gw)
+
+    // Spans are batched, hence they are exported by the flush.
+    if err = tp.ForceFlush(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+
+    spans := exp.GetSpans()
+    if len(spans) != 1 {
+        t.Fatalf("exported %d spans, want 1", len(spans))
+    }
+
+    attrs := map[attribute.Key]string{}
+    for _, kv := range spans[0].Resource.Attributes() {
+        attrs[kv.Key] = kv.Value.Emit()
+    }
+    for k, want := range map[attribute.Key]string{
+        semconv.ServiceNameKey:           "ping",
+        semconv.DeploymentEnvironmentKey: "test",
+        "team":                           "identity",
+        semconv.TelemetrySDKLanguageKey:  "go",
+    } {
+        if attrs[k] != want {
+            t.Errorf("resource attribute %s = %q, want %q", k, attrs[k], want)
+        }
+    }
+
+    if err = tkprovider.Shutdown(context.Background(), gw.Server(), tp); err != nil {
+        t.Fatal(err)
+    }
+}
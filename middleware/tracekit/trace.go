@@ -1,230 +1,334 @@
 package tracekit
 
 import (
+    "context"
+    "fmt"
     "strings"
+    "time"
 
     "github.com/clubpay/ronykit"
+    "go.opentelemetry.io/contrib/propagators/aws/xray"
     "go.opentelemetry.io/contrib/propagators/b3"
+    "go.opentelemetry.io/contrib/propagators/jaeger"
     "go.opentelemetry.io/otel"
     "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/baggage"
+    "go.opentelemetry.io/otel/codes"
     "go.opentelemetry.io/otel/propagation"
     semconv "go.opentelemetry.io/otel/semconv/v1.11.0"
     "go.opentelemetry.io/otel/trace"
 )
 
 const (
-    w3cTraceParent = "traceparent"
-    w3cState       = "tracestate"
-    b3Single       = "b3"
-    b3TraceID      = "x-b3-traceid"
-    b3ParentSpanID = "x-b3-parentspanid"
-    b3SpanID       = "x-b3-spanid"
-    b3Sampled      = "x-b3-sampled"
-    b3Flags        = "x-b3-flags"
+    errorCodeKey = attribute.Key("ronykit.error.code")
+    errorItemKey = attribute.Key("ronykit.error.item")
+
+    baggageAttributePrefix = "baggage."
 )
 
+// TracePropagator used to select the propagator of the tracing handler.
+//
+// Deprecated: the propagator is selected by the handler, i.e. B3, W3C, Jaeger or XRay, or set
+// by WithPropagator, which accepts any propagation.TextMapPropagator. TracePropagator is not
+// used anymore.
 type TracePropagator int
 
-const (
-    w3cPropagator TracePropagator = iota
-    b3Propagator
-)
-
+// B3 returns a tracing handler which propagates the span context in b3 headers. Both the single
+// and multi header encodings are extracted, and the injected one is set by B3Encoding.
 func B3(name string, opts ...Option) ronykit.HandlerFunc {
-    cfg := &config{
-        tracerName: name,
-        propagator: b3Propagator,
-    }
-    for _, opt := range opts {
-        opt(cfg)
+    cfg := newConfig(name, opts...)
+    if cfg.propagator == nil {
+        cfg.propagator = b3.New(b3.WithInjectEncoding(cfg.b3Encoding))
     }
 
     return withTracer(cfg)
 }
 
+// W3C returns a tracing handler which propagates the span context in traceparent and
+// tracestate headers.
 func W3C(name string, opts ...Option) ronykit.HandlerFunc {
-    cfg := &config{
-        tracerName: name,
-        propagator: w3cPropagator,
-    }
-    for _, opt := range opts {
-        opt(cfg)
+    cfg := newConfig(name, opts...)
+    if cfg.propagator == nil {
+        cfg.propagator = propagation.TraceContext{}
     }
 
     return withTracer(cfg)
 }
 
-func withTracer(cfg *config) ronykit.HandlerFunc {
-    var (
-        traceCtx     propagation.TextMapPropagator
-        traceCarrier func(ctx *ronykit.Context) propagation.TextMapCarrier
-    )
-
-    switch cfg.propagator {
-    case b3Propagator:
-        traceCtx = b3.New(b3.WithInjectEncoding(b3.B3SingleHeader))
-        traceCarrier = newB3Carrier
-    default:
-        traceCtx = propagation.TraceContext{}
-        traceCarrier = newW3CCarrier
+// Jaeger returns a tracing handler which propagates the span context in the uber-trace-id
+// header.
+func Jaeger(name string, opts ...Option) ronykit.HandlerFunc {
+    cfg := newConfig(name, opts...)
+    if cfg.propagator == nil {
+        cfg.propagator = jaeger.Jaeger{}
     }
 
-    var (
-        spanOpts []trace.SpanStartOption
-        kvs      []attribute.KeyValue
-    )
+    return withTracer(cfg)
+}
 
-    for k, v := range cfg.tags {
-        kvs = append(kvs, attribute.String(k, v))
+// XRay returns a tracing handler which propagates the span context in the X-Amzn-Trace-Id
+// header of AWS X-Ray.
+func XRay(name string, opts ...Option) ronykit.HandlerFunc {
+    cfg := newConfig(name, opts...)
+    if cfg.propagator == nil {
+        cfg.propagator = xray.Propagator{}
     }
 
-    if cfg.serviceName != "" {
-        kvs = append(kvs, semconv.ServiceNameKey.String(cfg.serviceName))
+    return withTracer(cfg)
+}
+
+// JaegerPropagator returns the propagator which is used by Jaeger, so it could be composed
+// with other propagators.
+func JaegerPropagator() propagation.TextMapPropagator {
+    return jaeger.Jaeger{}
+}
+
+// XRayPropagator returns the propagator which is used by XRay, so it could be composed with
+// other propagators.
+func XRayPropagator() propagation.TextMapPropagator {
+    return xray.Propagator{}
+}
+
+func withTracer(cfg *config) ronykit.HandlerFunc {
+    traceCtx := cfg.propagator
+    injector := cfg.injector
+    if injector == nil {
+        injector = traceCtx
     }
-    if cfg.env != "" {
-        kvs = append(kvs, semconv.DeploymentEnvironmentKey.String(cfg.env))
+    // The tracer of the provider is created once. Without a provider, the tracer is looked up
+    // from the global provider per request, so it follows otel.SetTracerProvider.
+    var tracer trace.Tracer
+    if cfg.provider != nil {
+        tracer = cfg.provider.Tracer(cfg.tracerName)
     }
 
-    if len(kvs) > 0 {
+    spanOpts := []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindServer)}
+    if kvs := cfg.attributes(); len(kvs) > 0 {
         spanOpts = append(spanOpts, trace.WithAttributes(kvs...))
     }
 
     return func(ctx *ronykit.Context) {
-        userCtx, span := otel.Tracer(cfg.tracerName).
-                Start(
-                    traceCtx.Extract(ctx.Context(), traceCarrier(ctx)),
-                    ctx.Route(),
-                    spanOpts...,
-                )
-
-        if cfg.dynTags != nil {
-            dynTags := cfg.dynTags(ctx.Limited())
-            kvs := make([]attribute.KeyValue, 0, len(dynTags))
-            for k, v := range cfg.dynTags(ctx.Limited()) {
-                kvs = append(kvs, attribute.String(k, v))
+        if cfg.sampler.skipped(ctx.Route()) {
+            ctx.Next()
+
+            return
+        }
+
+        carrier := ctxCarrier{ctx: ctx}
+        // Baggage is always extracted, but it is only injected if the propagator has it.
+        parentCtx := propagation.Baggage{}.Extract(ctx.Context(), carrier)
+        parentCtx = traceCtx.Extract(parentCtx, carrier)
+        parent := trace.SpanContextFromContext(parentCtx)
+        start := time.Now()
+
+        var (
+            userCtx context.Context
+            span    trace.Span
+        )
+        if cfg.sampler.sample(ctx.Route(), parent) {
+            tracer := tracer
+            if tracer == nil {
+                tracer = otel.Tracer(cfg.tracerName)
             }
-            span.SetAttributes(kvs...)
+            userCtx, span = startSpan(cfg, tracer, ctx, parentCtx, spanOpts...)
+        } else {
+            // No span is started, but an unsampled span context is propagated, so the
+            // downstream services make the same decision.
+            userCtx = trace.ContextWithSpanContext(parentCtx, unsampledSpanContext(parent))
         }
 
-        ctx.SetUserContext(userCtx)
-        ctx.Next()
+        // The span context is set as the preset headers of the context, hence it is sent
+        // with the REST response or any outgoing envelope of the RPC connections.
+        if cfg.inject {
+            injector.Inject(userCtx, carrier)
+        }
 
-        _, ok := ctx.Conn().(ronykit.RESTConn)
-        if ok {
-            span.SetAttributes(semconv.HTTPStatusCodeKey.Int(ctx.GetStatusCode()))
+        // ErrorMessage replies are recorded on the span when the handler returns.
+        var (
+            errs    []ronykit.ErrorMessage
+            resSize int
+        )
+        ctx.AddModifier(
+            func(e ronykit.Envelope) {
+                if em, ok := e.GetMsg().(ronykit.ErrorMessage); ok {
+                    errs = append(errs, em)
+                }
+                if cfg.resSize {
+                    if data, err := ronykit.MarshalMessage(e.GetMsg()); err == nil {
+                        resSize += len(data)
+                    }
+                }
+            },
+        )
+
+        finish := func(panicked interface{}) {
+            if span == nil {
+                if !cfg.sampler.sampleOnError || (panicked == nil && !failed(cfg, ctx, errs)) {
+                    return
+                }
+
+                // The deferred span is a new root, since the upstream span is not sampled,
+                // and it is linked to the upstream span instead.
+                opts := append(spanOpts[:len(spanOpts):len(spanOpts)], trace.WithTimestamp(start), trace.WithNewRoot())
+                if parent.IsValid() {
+                    opts = append(opts, trace.WithLinks(trace.Link{SpanContext: parent}))
+                }
+                _, span = startSpan(cfg, tracer, ctx, parentCtx, opts...)
+            }
+
+            endSpan(cfg, ctx, span, errs, resSize, panicked)
         }
 
-        span.End()
+        // Panics are recorded with their stack trace, and then the panic continues, so it
+        // could be handled by the server as before.
+        defer func() {
+            if r := recover(); r != nil {
+                finish(r)
+
+                panic(r)
+            }
+        }()
+
+        ctx.SetUserContext(userCtx)
+        ctx.Next()
+
+        finish(nil)
     }
 }
 
-type w3cCarrier struct {
-    traceParent string
-    traceState  string
-    ctx         *ronykit.Context
+// startSpan starts the span of the request and sets its attributes.
+func startSpan(
+        cfg *config, tracer trace.Tracer, ctx *ronykit.Context, parentCtx context.Context,
+        opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+    userCtx, span := tracer.
+            Start(
+                parentCtx,
+                cfg.spanName(ctx.Limited()),
+                opts...,
+            )
+
+    span.SetAttributes(connAttributes(ctx)...)
+    if len(cfg.baggageKeys) > 0 {
+        span.SetAttributes(baggageAttributes(userCtx, cfg.baggageKeys)...)
+    }
+
+    if cfg.dynTags != nil {
+        dynTags := cfg.dynTags(ctx.Limited())
+        kvs := make([]attribute.KeyValue, 0, len(dynTags))
+        for k, v := range dynTags {
+            kvs = append(kvs, attribute.String(k, v))
+        }
+        span.SetAttributes(kvs...)
+    }
+
+    return userCtx, span
 }
 
-func newW3CCarrier(ctx *ronykit.Context) propagation.TextMapCarrier {
-    c := w3cCarrier{ctx: ctx}
-    c.ctx.Conn().Walk(
-        func(key string, v string) bool {
-            if strings.EqualFold(w3cTraceParent, key) {
-                c.traceParent = v
-            } else if strings.EqualFold(w3cState, key) {
-                c.traceState = v
-            }
+// endSpan records the outcome of the request on the span and ends it. panicked is the value
+// which is recovered from the handler, if it panicked.
+func endSpan(
+        cfg *config, ctx *ronykit.Context, span trace.Span,
+        errs []ronykit.ErrorMessage, resSize int, panicked interface{},
+) {
+    for _, em := range errs {
+        recordErrorMessage(cfg, span, em)
+    }
 
-            return true
-        },
-    )
+    _, ok := ctx.Conn().(ronykit.RESTConn)
+    if ok {
+        span.SetAttributes(semconv.HTTPStatusCodeKey.Int(ctx.GetStatusCode()))
+        if cfg.resSize {
+            span.SetAttributes(semconv.HTTPResponseContentLengthKey.Int(resSize))
+        }
+        if cfg.isErrorCode(ctx.GetStatusCode()) {
+            span.SetStatus(codes.Error, ctx.GetStatusText())
+        }
+    }
+    // ronykit does not expose the error which is set by ctx.Error, hence only the status
+    // is set.
+    if ctx.HasError() {
+        span.SetStatus(codes.Error, "error is set by the handler")
+    }
+    if panicked != nil {
+        span.RecordError(fmt.Errorf("panic: %v", panicked), trace.WithStackTrace(true))
+        span.SetStatus(codes.Error, fmt.Sprintf("panic: %v", panicked))
+    }
 
-    return c
+    span.End()
 }
 
-func (c w3cCarrier) Get(key string) string {
-    switch key {
-    case w3cTraceParent:
-        return c.traceParent
-    case w3cState:
-        return c.traceState
+// failed reports if the request has failed, i.e. the handler has set an error, replied with an
+// error message, or the REST status code is an error.
+func failed(cfg *config, ctx *ronykit.Context, errs []ronykit.ErrorMessage) bool {
+    if ctx.HasError() {
+        return true
     }
-
-    v, ok := c.ctx.Get(key).(string)
-    if !ok {
-        return ""
+    for _, em := range errs {
+        if cfg.isErrorCode(em.GetCode()) {
+            return true
+        }
+    }
+    if _, ok := ctx.Conn().(ronykit.RESTConn); ok {
+        return cfg.isErrorCode(ctx.GetStatusCode())
     }
 
-    return v
+    return false
 }
 
-func (c w3cCarrier) Set(key string, value string) {
-    c.ctx.Set(key, value)
+// recordErrorMessage adds the code and item of the error message to the span, and sets its
+// status based on the code.
+func recordErrorMessage(cfg *config, span trace.Span, em ronykit.ErrorMessage) {
+    span.SetAttributes(
+        errorCodeKey.Int(em.GetCode()),
+        errorItemKey.String(em.GetItem()),
+    )
+    if cfg.isErrorCode(em.GetCode()) {
+        span.RecordError(em)
+        span.SetStatus(codes.Error, em.Error())
+    }
 }
 
-func (c w3cCarrier) Keys() []string {
-    var keys []string
-    c.ctx.Conn().Walk(
-        func(key string, _ string) bool {
-            keys = append(keys, key)
+// baggageAttributes returns the members of the baggage with the given keys as prefixed
+// attributes.
+func baggageAttributes(ctx context.Context, keys []string) []attribute.KeyValue {
+    b := baggage.FromContext(ctx)
+    kvs := make([]attribute.KeyValue, 0, len(keys))
+    for _, k := range keys {
+        m := b.Member(k)
+        if m.Key() == "" {
+            continue
+        }
 
-            return true
-        },
-    )
+        kvs = append(kvs, attribute.String(baggageAttributePrefix+k, m.Value()))
+    }
 
-    return keys
+    return kvs
 }
 
-type b3Carrier struct {
-    b3           string
-    traceID      string
-    parentSpanID string
-    spanID       string
-    sampled      string
-    flags        string
-    ctx          *ronykit.Context
+// ctxCarrier reads the headers of the incoming connection, and sets the injected headers as the
+// preset headers of the context.
+type ctxCarrier struct {
+    ctx *ronykit.Context
 }
 
-func newB3Carrier(ctx *ronykit.Context) propagation.TextMapCarrier {
-    b3.New()
-    c := b3Carrier{ctx: ctx}
+var _ propagation.TextMapCarrier = ctxCarrier{}
+
+func (c ctxCarrier) Get(key string) string {
+    var value string
     c.ctx.Conn().Walk(
-        func(key string, v string) bool {
-            switch {
-            case strings.EqualFold(b3Single, key):
-                c.b3 = v
-            case strings.EqualFold(b3TraceID, key):
-                c.traceID = v
-            case strings.EqualFold(b3SpanID, key):
-                c.spanID = v
-            case strings.EqualFold(b3ParentSpanID, key):
-                c.parentSpanID = v
-            case strings.EqualFold(b3Sampled, key):
-                c.sampled = v
-            case strings.EqualFold(b3Flags, key):
-                c.flags = v
+        func(k string, v string) bool {
+            if strings.EqualFold(k, key) {
+                value = v
+
+                return false
             }
 
             return true
         },
     )
-
-    return c
-}
-
-func (c b3Carrier) Get(key string) string {
-    switch key {
-    case b3Single:
-        return c.b3
-    case b3TraceID:
-        return c.traceID
-    case b3SpanID:
-        return c.spanID
-    case b3ParentSpanID:
-        return c.parentSpanID
-    case b3Sampled:
-        return c.sampled
-    case b3Flags:
-        return c.flags
+    if value != "" {
+        return value
     }
 
     v, ok := c.ctx.Get(key).(string)
@@ -235,11 +339,11 @@ func (c b3Carrier) Get(key string) string {
     return v
 }
 
-func (c b3Carrier) Set(key string, value string) {
-    c.ctx.Set(key, value)
+func (c ctxCarrier) Set(key string, value string) {
+    c.ctx.PresetHdr(key, value)
 }
 
-func (c b3Carrier) Keys() []string {
+func (c ctxCarrier) Keys() []string {
     var keys []string
     c.ctx.Conn().Walk(
         func(key string, _ string) bool {
@@ -0,0 +1,538 @@
+package tracekit_test
+
+import (
+    "fmt"
+    "net/http"
+    "strings"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/middleware/tracekit"
+    "github.com/clubpay/ronycontrib/middleware/tracekit/tracekittest"
+    "github.com/clubpay/ronykit"
+    "github.com/clubpay/ronykit/desc"
+    "github.com/clubpay/ronykit/std/gateway/fasthttp"
+    "go.opentelemetry.io/contrib/propagators/b3"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/propagation"
+    semconv "go.opentelemetry.io/otel/semconv/v1.11.0"
+    "go.opentelemetry.io/otel/trace"
+)
+
+const (
+    restRoute = "GET /users/:id"
+    rpcRoute  = "getUser"
+)
+
+type userReq struct {
+    ID string `json:"id"`
+}
+
+type userRes struct {
+    ID   string `json:"id"`
+    Name string `json:"name"`
+}
+
+type userError struct {
+    Code int    `json:"code"`
+    Item string `json:"item"`
+}
+
+var _ ronykit.ErrorMessage = (*userError)(nil)
+
+func (e userError) GetCode() int {
+    return e.Code
+}
+
+func (e userError) GetItem() string {
+    return e.Item
+}
+
+func (e userError) Error() string {
+    return fmt.Sprintf("%d: %s", e.Code, e.Item)
+}
+
+func okHandler(ctx *ronykit.Context) {
+    ctx.SetStatusCode(http.StatusOK)
+    ctx.Out().SetMsg(&userRes{ID: "1", Name: "user"}).Send()
+}
+
+func errorHandler(code int) ronykit.HandlerFunc {
+    return func(ctx *ronykit.Context) {
+        ctx.SetStatusCode(code)
+        ctx.Out().SetMsg(&userError{Code: code, Item: "USER"}).Send()
+    }
+}
+
+// newGateway serves the users service, whose contract has both the REST and RPC routes, with
+// the tracing handler as a global handler.
+func newGateway(tracer ronykit.HandlerFunc, handlers ...ronykit.HandlerFunc) *tracekittest.Gateway {
+    svc := desc.NewService("users").
+        AddContract(
+            desc.NewContract().
+                SetName("getUser").
+                SetInput(&userReq{}).
+                SetOutput(&userRes{}).
+                AddSelector(fasthttp.GET("/users/:id")).
+                AddSelector(fasthttp.RPC(rpcRoute)).
+                SetHandler(handlers...),
+        )
+
+    return tracekittest.NewGateway(ronykit.WithGlobalHandlers(tracer)).Serve(svc.Generate())
+}
+
+func parentSpanContext(sampled bool) trace.SpanContext {
+    scc := trace.SpanContextConfig{
+        TraceID: trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+        SpanID:  trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+        Remote:  true,
+    }
+    if sampled {
+        scc.TraceFlags = trace.FlagsSampled
+    }
+
+    return trace.NewSpanContext(scc)
+}
+
+func send(t *testing.T, gw *tracekittest.Gateway, conn ronykit.Conn, route string) []tracekittest.Envelope {
+    t.Helper()
+
+    out, err := gw.Send(conn, route, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(out) == 0 {
+        t.Fatal("no envelope is sent")
+    }
+
+    return out
+}
+
+func TestREST(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    gw := newGateway(
+        tracekit.W3C("users", tracekit.WithTracerProvider(rec.TracerProvider()), tracekit.ResponseContentLength(true)),
+        okHandler,
+    )
+
+    parent := parentSpanContext(true)
+    hdr := tracekittest.Headers(propagation.TraceContext{}, parent)
+    hdr["User-Agent"] = "test-agent"
+    hdr["Host"] = "example.com"
+    hdr["Content-Length"] = "12"
+    conn := tracekittest.NewRESTConn(http.MethodGet, "/users/1?x=1", hdr).SetClientIP("10.0.0.1")
+    out := send(t, gw, conn, restRoute)
+
+    tracekittest.AssertSpanNames(t, rec.Spans(), restRoute)
+    span := rec.Span(restRoute)
+    if span.SpanKind() != trace.SpanKindServer {
+        t.Errorf("span kind = %s, want %s", span.SpanKind(), trace.SpanKindServer)
+    }
+    tracekittest.AssertChildOf(t, span, parent)
+    tracekittest.AssertStatus(t, span, codes.Unset)
+    tracekittest.AssertAttributes(
+        t, span,
+        attribute.String("ronykit.contract.id", "users.getUser"),
+        semconv.HTTPMethodKey.String(http.MethodGet),
+        semconv.HTTPRouteKey.String("/users/:id"),
+        semconv.HTTPTargetKey.String("/users/1?x=1"),
+        semconv.HTTPSchemeKey.String("http"),
+        semconv.HTTPHostKey.String("example.com"),
+        semconv.HTTPUserAgentKey.String("test-agent"),
+        semconv.HTTPClientIPKey.String("10.0.0.1"),
+        semconv.HTTPRequestContentLengthKey.Int(12),
+        semconv.HTTPStatusCodeKey.Int(http.StatusOK),
+        semconv.HTTPResponseContentLengthKey.Int(len(`{"id":"1","name":"user"}`)),
+    )
+    tracekittest.AssertNoAttributes(t, span, semconv.RPCSystemKey)
+    tracekittest.AssertPropagated(t, propagation.TraceContext{}, out[0].Hdr, span)
+}
+
+func TestRPC(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    gw := newGateway(tracekit.W3C("users", tracekit.WithTracerProvider(rec.TracerProvider())), okHandler)
+
+    parent := parentSpanContext(true)
+    conn := tracekittest.NewConn(tracekittest.Headers(propagation.TraceContext{}, parent)).SetClientIP("10.0.0.1")
+    out := send(t, gw, conn, rpcRoute)
+
+    tracekittest.AssertSpanNames(t, rec.Spans(), rpcRoute)
+    span := rec.Span(rpcRoute)
+    tracekittest.AssertChildOf(t, span, parent)
+    tracekittest.AssertAttributes(
+        t, span,
+        semconv.RPCSystemKey.String("ronykit"),
+        semconv.RPCServiceKey.String("users"),
+        semconv.RPCMethodKey.String(rpcRoute),
+        semconv.NetPeerIPKey.String("10.0.0.1"),
+    )
+    tracekittest.AssertNoAttributes(t, span, semconv.HTTPMethodKey, semconv.HTTPStatusCodeKey)
+    tracekittest.AssertPropagated(t, propagation.TraceContext{}, out[0].Hdr, span)
+}
+
+func TestInject(t *testing.T) {
+    parent := parentSpanContext(true)
+
+    for _, tc := range []struct {
+        name    string
+        opts    []tracekit.Option
+        present []string
+        absent  []string
+    }{
+        {name: "Default", present: []string{"b3"}, absent: []string{"traceparent"}},
+        {name: "Disabled", opts: []tracekit.Option{tracekit.Inject(false)}, absent: []string{"b3", "traceparent"}},
+        {
+            name:    "InjectPropagator",
+            opts:    []tracekit.Option{tracekit.WithInjectPropagator(propagation.TraceContext{})},
+            present: []string{"traceparent"},
+            absent:  []string{"b3"},
+        },
+        {
+            name: "CompositeInjectPropagator",
+            opts: []tracekit.Option{
+                tracekit.WithInjectPropagator(
+                    propagation.NewCompositeTextMapPropagator(b3.New(), propagation.TraceContext{}),
+                ),
+            },
+            present: []string{"b3", "traceparent"},
+        },
+    } {
+        t.Run(tc.name, func(t *testing.T) {
+            rec := tracekittest.NewRecorder()
+            opts := append([]tracekit.Option{tracekit.WithTracerProvider(rec.TracerProvider())}, tc.opts...)
+            gw := newGateway(tracekit.B3("users", opts...), okHandler)
+
+            // The b3 header is extracted regardless of the injected headers, and both the REST
+            // responses and the RPC envelopes carry the injected ones.
+            for route, conn := range map[string]ronykit.Conn{
+                restRoute: tracekittest.NewRESTConn(http.MethodGet, "/users/1", tracekittest.Headers(b3.New(), parent)),
+                rpcRoute:  tracekittest.NewConn(tracekittest.Headers(b3.New(), parent)),
+            } {
+                out := send(t, gw, conn, route)
+                span := rec.Span(route)
+                tracekittest.AssertChildOf(t, span, parent)
+
+                for _, h := range tc.present {
+                    if _, ok := out[0].Hdr[h]; !ok {
+                        t.Errorf("%s: header %s is not injected: %v", route, h, out[0].Hdr)
+                    }
+                }
+                for _, h := range tc.absent {
+                    if _, ok := out[0].Hdr[h]; ok {
+                        t.Errorf("%s: header %s is injected: %v", route, h, out[0].Hdr)
+                    }
+                }
+                if _, ok := out[0].Hdr["traceparent"]; ok {
+                    tracekittest.AssertPropagated(t, propagation.TraceContext{}, out[0].Hdr, span)
+                }
+            }
+        })
+    }
+}
+
+func TestErrors(t *testing.T) {
+    for _, tc := range []struct {
+        name    string
+        handler ronykit.HandlerFunc
+        opts    []tracekit.Option
+        status  codes.Code
+    }{
+        {name: "Success", handler: okHandler, status: codes.Unset},
+        {name: "ServerError", handler: errorHandler(http.StatusInternalServerError), status: codes.Error},
+        {name: "ClientError", handler: errorHandler(http.StatusNotFound), status: codes.Unset},
+        {
+            name:    "ClientErrorEnabled",
+            handler: errorHandler(http.StatusNotFound),
+            opts:    []tracekit.Option{tracekit.ClientErrors(true)},
+            status:  codes.Error,
+        },
+        {
+            name: "ContextError",
+            handler: func(ctx *ronykit.Context) {
+                ctx.Error(fmt.Errorf("failed"))
+                okHandler(ctx)
+            },
+            status: codes.Error,
+        },
+    } {
+        t.Run(tc.name, func(t *testing.T) {
+            rec := tracekittest.NewRecorder()
+            opts := append([]tracekit.Option{tracekit.WithTracerProvider(rec.TracerProvider())}, tc.opts...)
+            gw := newGateway(tracekit.W3C("users", opts...), tc.handler)
+
+            send(t, gw, tracekittest.NewConn(nil), rpcRoute)
+            tracekittest.AssertSpanNames(t, rec.Spans(), rpcRoute)
+            tracekittest.AssertStatus(t, rec.Span(rpcRoute), tc.status)
+        })
+    }
+
+    t.Run("ErrorMessage", func(t *testing.T) {
+        rec := tracekittest.NewRecorder()
+        gw := newGateway(
+            tracekit.W3C("users", tracekit.WithTracerProvider(rec.TracerProvider())),
+            errorHandler(http.StatusServiceUnavailable),
+        )
+
+        send(t, gw, tracekittest.NewRESTConn(http.MethodGet, "/users/1", nil), restRoute)
+        span := rec.Span(restRoute)
+        tracekittest.AssertAttributes(
+            t, span,
+            attribute.Int("ronykit.error.code", http.StatusServiceUnavailable),
+            attribute.String("ronykit.error.item", "USER"),
+            semconv.HTTPStatusCodeKey.Int(http.StatusServiceUnavailable),
+        )
+        if len(span.Events()) != 1 || span.Events()[0].Name != "exception" {
+            t.Errorf("span events = %v, want one exception", span.Events())
+        }
+    })
+
+    t.Run("Panic", func(t *testing.T) {
+        rec := tracekittest.NewRecorder()
+        gw := newGateway(
+            tracekit.W3C("users", tracekit.WithTracerProvider(rec.TracerProvider())),
+            func(ctx *ronykit.Context) {
+                panic("boom")
+            },
+        )
+
+        func() {
+            defer func() {
+                if r := recover(); r != "boom" {
+                    t.Errorf("recovered %v, want the panic of the handler", r)
+                }
+            }()
+
+            _, _ = gw.Send(tracekittest.NewConn(nil), rpcRoute, nil)
+        }()
+
+        tracekittest.AssertSpanNames(t, rec.Spans(), rpcRoute)
+        span := rec.Span(rpcRoute)
+        tracekittest.AssertStatus(t, span, codes.Error)
+        if span.Status().Description != "panic: boom" {
+            t.Errorf("status description = %q, want %q", span.Status().Description, "panic: boom")
+        }
+    })
+}
+
+func TestErrorMessages(t *testing.T) {
+    for _, tc := range []struct {
+        name   string
+        code   int
+        opts   []tracekit.Option
+        status codes.Code
+        events int
+    }{
+        {name: "ServerError", code: http.StatusBadGateway, status: codes.Error, events: 1},
+        {name: "ClientError", code: http.StatusConflict, status: codes.Unset},
+        {
+            name:   "ClientErrorEnabled",
+            code:   http.StatusConflict,
+            opts:   []tracekit.Option{tracekit.ClientErrors(true)},
+            status: codes.Error,
+            events: 1,
+        },
+    } {
+        t.Run(tc.name, func(t *testing.T) {
+            rec := tracekittest.NewRecorder()
+            opts := append([]tracekit.Option{tracekit.WithTracerProvider(rec.TracerProvider())}, tc.opts...)
+            gw := newGateway(tracekit.W3C("users", opts...), errorHandler(tc.code))
+
+            // RPC connections have no status code, hence the status is set by the error message.
+            send(t, gw, tracekittest.NewConn(nil), rpcRoute)
+            span := rec.Span(rpcRoute)
+            tracekittest.AssertStatus(t, span, tc.status)
+            tracekittest.AssertAttributes(
+                t, span,
+                attribute.Int("ronykit.error.code", tc.code),
+                attribute.String("ronykit.error.item", "USER"),
+            )
+            if len(span.Events()) != tc.events {
+                t.Errorf("span has %d events, want %d", len(span.Events()), tc.events)
+            }
+        })
+    }
+}
+
+func TestPanicStackTrace(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    gw := newGateway(
+        tracekit.W3C("users", tracekit.WithTracerProvider(rec.TracerProvider())),
+        func(ctx *ronykit.Context) {
+            panic(fmt.Errorf("boom"))
+        },
+    )
+
+    func() {
+        defer func() {
+            if r := recover(); r == nil {
+                t.Errorf("panic is not re-raised")
+            }
+        }()
+
+        _, _ = gw.Send(tracekittest.NewRESTConn(http.MethodGet, "/users/1", nil), restRoute, nil)
+    }()
+
+    span := rec.Span(restRoute)
+    if span == nil {
+        t.Fatal("span is not ended by the panic")
+    }
+    if len(span.Events()) != 1 {
+        t.Fatalf("span has %d events, want 1", len(span.Events()))
+    }
+
+    attrs := map[attribute.Key]string{}
+    for _, kv := range span.Events()[0].Attributes {
+        attrs[kv.Key] = kv.Value.Emit()
+    }
+    if attrs[semconv.ExceptionMessageKey] != "panic: boom" {
+        t.Errorf("exception message = %q", attrs[semconv.ExceptionMessageKey])
+    }
+    if !strings.Contains(attrs[semconv.ExceptionStacktraceKey], "TestPanicStackTrace") {
+        t.Errorf("exception stack trace does not contain the handler:\n%s", attrs[semconv.ExceptionStacktraceKey])
+    }
+}
+
+func TestSpanName(t *testing.T) {
+    for _, tc := range []struct {
+        name  string
+        opts  []tracekit.Option
+        names map[string]string
+    }{
+        {name: "Default", names: map[string]string{restRoute: restRoute, rpcRoute: rpcRoute}},
+        {
+            name: "Formatter",
+            opts: []tracekit.Option{
+                tracekit.WithSpanName(
+                    func(ctx *ronykit.LimitedContext) string {
+                        if _, ok := ctx.Conn().(ronykit.RESTConn); ok {
+                            return ctx.Route()
+                        }
+
+                        return fmt.Sprintf("%s.%s", ctx.ServiceName(), ctx.Route())
+                    },
+                ),
+            },
+            names: map[string]string{restRoute: restRoute, rpcRoute: "users." + rpcRoute},
+        },
+    } {
+        t.Run(tc.name, func(t *testing.T) {
+            rec := tracekittest.NewRecorder()
+            opts := append([]tracekit.Option{tracekit.WithTracerProvider(rec.TracerProvider())}, tc.opts...)
+            gw := newGateway(tracekit.W3C("users", opts...), okHandler)
+
+            send(t, gw, tracekittest.NewRESTConn(http.MethodGet, "/users/1", nil), restRoute)
+            send(t, gw, tracekittest.NewConn(nil), rpcRoute)
+
+            tracekittest.AssertSpanNames(t, rec.Spans(), tc.names[restRoute], tc.names[rpcRoute])
+            for _, span := range rec.Spans() {
+                if span.SpanKind() != trace.SpanKindServer {
+                    t.Errorf("span kind of %s = %s, want %s", span.Name(), span.SpanKind(), trace.SpanKindServer)
+                }
+            }
+        })
+    }
+}
+
+func TestOptions(t *testing.T) {
+    rec := tracekittest.NewRecorder()
+    gw := newGateway(
+        tracekit.W3C(
+            "users",
+            tracekit.WithTracerProvider(rec.TracerProvider()),
+            tracekit.ServiceName("users-api"),
+            tracekit.Env("test"),
+            tracekit.WithTags(map[string]string{"team": "identity"}),
+            tracekit.WithDynamicTags(
+                func(ctx *ronykit.LimitedContext) map[string]string {
+                    return map[string]string{"service": ctx.ServiceName()}
+                },
+            ),
+            tracekit.WithSpanName(
+                func(ctx *ronykit.LimitedContext) string {
+                    return fmt.Sprintf("%s.%s", ctx.ServiceName(), ctx.Route())
+                },
+            ),
+            tracekit.BaggageAttributes("tenant"),
+            tracekit.Inject(false),
+        ),
+        okHandler,
+    )
+
+    out := send(t, gw, tracekittest.NewConn(map[string]string{"baggage": "tenant=acme,other=1"}), rpcRoute)
+
+    tracekittest.AssertSpanNames(t, rec.Spans(), "users."+rpcRoute)
+    span := rec.Span("users." + rpcRoute)
+    tracekittest.AssertAttributes(
+        t, span,
+        semconv.ServiceNameKey.String("users-api"),
+        semconv.DeploymentEnvironmentKey.String("test"),
+        attribute.String("team", "identity"),
+        attribute.String("service", "users"),
+        attribute.String("baggage.tenant", "acme"),
+    )
+    tracekittest.AssertNoAttributes(t, span, "tenant", "baggage.other")
+    if _, ok := out[0].Hdr["traceparent"]; ok {
+        t.Errorf("traceparent is injected, while Inject is disabled")
+    }
+}
+
+// countingProvider counts the tracers which are created by the handler.
+type countingProvider struct {
+    trace.TracerProvider
+    tracers int
+}
+
+func (p *countingProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+    p.tracers++
+
+    return p.TracerProvider.Tracer(name, opts...)
+}
+
+func TestTracerProvider(t *testing.T) {
+    t.Run("CachedTracer", func(t *testing.T) {
+        rec := tracekittest.NewRecorder()
+        tp := &countingProvider{TracerProvider: rec.TracerProvider()}
+        gw := newGateway(tracekit.W3C("users", tracekit.WithTracerProvider(tp)), okHandler)
+
+        for i := 0; i < 3; i++ {
+            send(t, gw, tracekittest.NewConn(nil), rpcRoute)
+        }
+        if tp.tracers != 1 {
+            t.Errorf("created %d tracers, want 1", tp.tracers)
+        }
+        if len(rec.Spans()) != 3 {
+            t.Errorf("recorded %d spans, want 3", len(rec.Spans()))
+        }
+    })
+
+    t.Run("GlobalProvider", func(t *testing.T) {
+        prev := otel.GetTracerProvider()
+        defer otel.SetTracerProvider(prev)
+
+        // The handler is created before the global provider is set, and it follows the
+        // providers which are set later.
+        gw := newGateway(tracekit.W3C("users"), okHandler)
+        for i := 0; i < 2; i++ {
+            rec := tracekittest.NewRecorder()
+            otel.SetTracerProvider(rec.TracerProvider())
+
+            send(t, gw, tracekittest.NewConn(nil), rpcRoute)
+            tracekittest.AssertSpanNames(t, rec.Spans(), rpcRoute)
+        }
+    })
+}
+
+func TestNoopProvider(t *testing.T) {
+    gw := newGateway(
+        tracekit.W3C("users", tracekit.WithTracerProvider(trace.NewNoopTracerProvider())),
+        okHandler,
+    )
+
+    parent := parentSpanContext(true)
+    hdr := tracekittest.Headers(propagation.TraceContext{}, parent)
+    out := send(t, gw, tracekittest.NewConn(hdr), rpcRoute)
+
+    // The upstream span context is propagated unchanged.
+    tracekittest.AssertHeader(t, out[0].Hdr, "traceparent", hdr["traceparent"])
+}
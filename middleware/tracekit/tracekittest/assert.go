@@ -0,0 +1,139 @@
+package tracekittest
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/propagation"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/trace"
+)
+
+// Headers returns the headers which carry the span context by the propagator, to be used as
+// the headers of the incoming requests.
+func Headers(p propagation.TextMapPropagator, sc trace.SpanContext) map[string]string {
+    carrier := propagation.MapCarrier{}
+    p.Inject(trace.ContextWithSpanContext(context.Background(), sc), carrier)
+
+    return carrier
+}
+
+// AssertSpanNames checks that the names of the spans are the same as names, in order.
+func AssertSpanNames(t testing.TB, spans []sdktrace.ReadOnlySpan, names ...string) {
+    t.Helper()
+
+    got := make([]string, 0, len(spans))
+    for _, s := range spans {
+        got = append(got, s.Name())
+    }
+    if len(got) != len(names) {
+        t.Errorf("span names = %q, want %q", got, names)
+
+        return
+    }
+    for idx := range got {
+        if got[idx] != names[idx] {
+            t.Errorf("span names = %q, want %q", got, names)
+
+            return
+        }
+    }
+}
+
+// AssertAttributes checks that the span has all the attributes with the same values.
+func AssertAttributes(t testing.TB, span sdktrace.ReadOnlySpan, kvs ...attribute.KeyValue) {
+    t.Helper()
+
+    attrs := attributes(span)
+    for _, kv := range kvs {
+        v, ok := attrs[kv.Key]
+        switch {
+        case !ok:
+            t.Errorf("span %q has no attribute %s", span.Name(), kv.Key)
+        case v != kv.Value:
+            t.Errorf("span %q attribute %s = %s, want %s", span.Name(), kv.Key, v.Emit(), kv.Value.Emit())
+        }
+    }
+}
+
+// AssertNoAttributes checks that the span has none of the attributes.
+func AssertNoAttributes(t testing.TB, span sdktrace.ReadOnlySpan, keys ...attribute.Key) {
+    t.Helper()
+
+    attrs := attributes(span)
+    for _, k := range keys {
+        if v, ok := attrs[k]; ok {
+            t.Errorf("span %q has attribute %s = %s", span.Name(), k, v.Emit())
+        }
+    }
+}
+
+// AssertStatus checks the status code of the span.
+func AssertStatus(t testing.TB, span sdktrace.ReadOnlySpan, code codes.Code) {
+    t.Helper()
+
+    if got := span.Status().Code; got != code {
+        t.Errorf("span %q status = %s, want %s", span.Name(), got, code)
+    }
+}
+
+// AssertChildOf checks that the span is a child of the parent, i.e. it belongs to the trace of
+// the parent, and its parent span is the parent.
+func AssertChildOf(t testing.TB, span sdktrace.ReadOnlySpan, parent trace.SpanContext) {
+    t.Helper()
+
+    if got := span.SpanContext().TraceID(); got != parent.TraceID() {
+        t.Errorf("span %q trace id = %s, want %s", span.Name(), got, parent.TraceID())
+    }
+    if got := span.Parent().SpanID(); got != parent.SpanID() {
+        t.Errorf("span %q parent span id = %s, want %s", span.Name(), got, parent.SpanID())
+    }
+}
+
+// AssertHeader checks the value of the header, whose key is matched case-insensitively.
+func AssertHeader(t testing.TB, hdr map[string]string, key, want string) {
+    t.Helper()
+
+    for k, v := range hdr {
+        if strings.EqualFold(k, key) {
+            if v != want {
+                t.Errorf("header %s = %q, want %q", key, v, want)
+            }
+
+            return
+        }
+    }
+
+    t.Errorf("header %s is not set, want %q", key, want)
+}
+
+// AssertPropagated checks that the headers carry the span context of the span, as it is
+// extracted by the propagator.
+func AssertPropagated(t testing.TB, p propagation.TextMapPropagator, hdr map[string]string, span sdktrace.ReadOnlySpan) {
+    t.Helper()
+
+    sc := trace.SpanContextFromContext(p.Extract(context.Background(), propagation.MapCarrier(hdr)))
+    switch {
+    case !sc.IsValid():
+        t.Errorf("headers %v carry no span context", hdr)
+    case sc.TraceID() != span.SpanContext().TraceID() || sc.SpanID() != span.SpanContext().SpanID():
+        t.Errorf(
+            "headers carry span %s-%s, want %s-%s",
+            sc.TraceID(), sc.SpanID(), span.SpanContext().TraceID(), span.SpanContext().SpanID(),
+        )
+    case sc.IsSampled() != span.SpanContext().IsSampled():
+        t.Errorf("headers carry sampled = %t, want %t", sc.IsSampled(), span.SpanContext().IsSampled())
+    }
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+    attrs := map[attribute.Key]attribute.Value{}
+    for _, kv := range span.Attributes() {
+        attrs[kv.Key] = kv.Value
+    }
+
+    return attrs
+}
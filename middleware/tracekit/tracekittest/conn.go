@@ -0,0 +1,177 @@
+package tracekittest
+
+import (
+    "errors"
+    "mime/multipart"
+    "net/url"
+    "sort"
+    "sync"
+    "sync/atomic"
+)
+
+const (
+    headerHost  = "Host"
+    defaultHost = "localhost"
+)
+
+var (
+    lastConnID uint64
+
+    errFormNotSupported = errors.New("form is not supported by the fake connection")
+)
+
+// Conn is a fake ronykit.Conn, which holds the headers of the request. Walk visits the headers
+// in the order of their keys, so the tests are deterministic.
+type Conn struct {
+    mtx      sync.Mutex
+    id       uint64
+    clientIP string
+    stream   bool
+    hdr      map[string]string
+}
+
+// NewConn returns a fake RPC connection with the headers.
+func NewConn(hdr map[string]string) *Conn {
+    c := &Conn{
+        id:     atomic.AddUint64(&lastConnID, 1),
+        stream: true,
+        hdr:    map[string]string{},
+    }
+    for k, v := range hdr {
+        c.hdr[k] = v
+    }
+
+    return c
+}
+
+// SetClientIP sets the IP address of the client.
+func (c *Conn) SetClientIP(ip string) *Conn {
+    c.clientIP = ip
+
+    return c
+}
+
+func (c *Conn) ConnID() uint64 {
+    return c.id
+}
+
+func (c *Conn) ClientIP() string {
+    return c.clientIP
+}
+
+func (c *Conn) Write(data []byte) (int, error) {
+    return len(data), nil
+}
+
+func (c *Conn) Stream() bool {
+    return c.stream
+}
+
+func (c *Conn) Walk(f func(key string, val string) bool) {
+    c.mtx.Lock()
+    keys := make([]string, 0, len(c.hdr))
+    for k := range c.hdr {
+        keys = append(keys, k)
+    }
+    hdr := make(map[string]string, len(c.hdr))
+    for k, v := range c.hdr {
+        hdr[k] = v
+    }
+    c.mtx.Unlock()
+
+    sort.Strings(keys)
+    for _, k := range keys {
+        if !f(k, hdr[k]) {
+            return
+        }
+    }
+}
+
+func (c *Conn) Get(key string) string {
+    c.mtx.Lock()
+    defer c.mtx.Unlock()
+
+    return c.hdr[key]
+}
+
+func (c *Conn) Set(key string, val string) {
+    c.mtx.Lock()
+    c.hdr[key] = val
+    c.mtx.Unlock()
+}
+
+// RESTConn is a fake ronykit.RESTConn, which holds the request line and the headers of the
+// request, and the status code of the response.
+type RESTConn struct {
+    Conn
+
+    method     string
+    host       string
+    path       string
+    requestURI string
+    statusCode int
+}
+
+// NewRESTConn returns a fake REST connection. The host is set by the Host header, or it is
+// localhost.
+func NewRESTConn(method, requestURI string, hdr map[string]string) *RESTConn {
+    c := &RESTConn{
+        method:     method,
+        host:       defaultHost,
+        path:       requestURI,
+        requestURI: requestURI,
+    }
+    c.id = atomic.AddUint64(&lastConnID, 1)
+    c.hdr = map[string]string{}
+    for k, v := range hdr {
+        c.hdr[k] = v
+    }
+    if host := c.hdr[headerHost]; host != "" {
+        c.host = host
+    }
+    if u, err := url.ParseRequestURI(requestURI); err == nil {
+        c.path = u.Path
+    }
+
+    return c
+}
+
+// SetClientIP sets the IP address of the client.
+func (c *RESTConn) SetClientIP(ip string) *RESTConn {
+    c.clientIP = ip
+
+    return c
+}
+
+func (c *RESTConn) GetMethod() string {
+    return c.method
+}
+
+func (c *RESTConn) GetHost() string {
+    return c.host
+}
+
+func (c *RESTConn) GetRequestURI() string {
+    return c.requestURI
+}
+
+func (c *RESTConn) GetPath() string {
+    return c.path
+}
+
+func (c *RESTConn) Form() (*multipart.Form, error) {
+    return nil, errFormNotSupported
+}
+
+func (c *RESTConn) SetStatusCode(code int) {
+    c.statusCode = code
+}
+
+// StatusCode returns the status code which is set by the handlers.
+func (c *RESTConn) StatusCode() int {
+    return c.statusCode
+}
+
+func (c *RESTConn) Redirect(code int, _ string) {
+    c.statusCode = code
+}
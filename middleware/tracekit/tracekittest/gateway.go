@@ -0,0 +1,155 @@
+package tracekittest
+
+import (
+    "context"
+    "fmt"
+    "sync"
+
+    "github.com/clubpay/ronykit"
+)
+
+// Envelope is a copy of an envelope which is sent to the connection.
+type Envelope struct {
+    Hdr map[string]string
+    Msg ronykit.Message
+}
+
+type route struct {
+    serviceName string
+    contractID  string
+    route       string
+    factory     ronykit.MessageFactoryFunc
+}
+
+type request struct {
+    route route
+    msg   ronykit.Message
+    out   []Envelope
+}
+
+// Gateway is an in-process ronykit.Gateway, which runs the requests on the fake connections
+// through a real EdgeServer, so the handlers get a ronykit.Context like in production.
+//
+//	gw := tracekittest.NewGateway(ronykit.WithGlobalHandlers(tracekit.W3C("users"))).Serve(svc)
+//	out, err := gw.Send(tracekittest.NewRESTConn("GET", "/users/1", nil), "GET /users/:id", nil)
+type Gateway struct {
+    mtx    sync.Mutex
+    opts   []ronykit.Option
+    srv    *ronykit.EdgeServer
+    d      ronykit.GatewayDelegate
+    routes map[string]route
+    req    *request
+}
+
+var _ ronykit.Gateway = (*Gateway)(nil)
+
+// NewGateway returns a gateway, which creates its EdgeServer with the options.
+func NewGateway(opts ...ronykit.Option) *Gateway {
+    return &Gateway{
+        opts:   opts,
+        routes: map[string]route{},
+    }
+}
+
+// Serve registers the services in a new EdgeServer and starts it.
+func (gw *Gateway) Serve(services ...ronykit.Service) *Gateway {
+    gw.srv = ronykit.NewServer(gw.opts...)
+    gw.srv.RegisterBundle(gw)
+    for _, svc := range services {
+        gw.srv.RegisterService(svc)
+    }
+    gw.srv.Start(context.Background())
+
+    return gw
+}
+
+// Server returns the EdgeServer of the gateway, which is nil before Serve.
+func (gw *Gateway) Server() *ronykit.EdgeServer {
+    return gw.srv
+}
+
+// Send runs the request of the route on the connection, and returns the envelopes which are
+// sent to it. REST routes are identified by "{method} {path}", e.g. "GET /users/:id", and RPC
+// routes by their predicate. If msg is nil, the input message of the contract is used.
+// Requests are run one at a time.
+func (gw *Gateway) Send(conn ronykit.Conn, routeName string, msg ronykit.Message) ([]Envelope, error) {
+    gw.mtx.Lock()
+    defer gw.mtx.Unlock()
+
+    r, ok := gw.routes[routeName]
+    if !ok {
+        return nil, fmt.Errorf("route %q is not registered", routeName)
+    }
+    if msg == nil {
+        msg = r.factory()
+    }
+
+    gw.req = &request{route: r, msg: msg}
+    defer func() {
+        gw.req = nil
+    }()
+
+    gw.d.OnMessage(conn, gw.write, nil)
+
+    return gw.req.out, nil
+}
+
+func (gw *Gateway) Start(_ context.Context) error {
+    return nil
+}
+
+func (gw *Gateway) Shutdown(_ context.Context) error {
+    return nil
+}
+
+func (gw *Gateway) Register(
+        serviceName, contractID string, _ ronykit.Encoding, sel ronykit.RouteSelector, input ronykit.Message,
+) {
+    r := route{
+        serviceName: serviceName,
+        contractID:  contractID,
+        factory:     ronykit.CreateMessageFactory(input),
+    }
+
+    if rs, ok := sel.(ronykit.RESTRouteSelector); ok && rs.GetMethod() != "" && rs.GetPath() != "" {
+        r.route = fmt.Sprintf("%s %s", rs.GetMethod(), rs.GetPath())
+        gw.routes[r.route] = r
+    }
+    if rs, ok := sel.(ronykit.RPCRouteSelector); ok && rs.GetPredicate() != "" {
+        r.route = rs.GetPredicate()
+        gw.routes[r.route] = r
+    }
+}
+
+func (gw *Gateway) Subscribe(d ronykit.GatewayDelegate) {
+    gw.d = d
+}
+
+func (gw *Gateway) Dispatch(ctx *ronykit.Context, _ []byte) (ronykit.ExecuteArg, error) {
+    ctx.In().
+        SetHdrWalker(ctx.Conn()).
+        SetMsg(gw.req.msg)
+
+    return ronykit.ExecuteArg{
+        ServiceName: gw.req.route.serviceName,
+        ContractID:  gw.req.route.contractID,
+        Route:       gw.req.route.route,
+    }, nil
+}
+
+func (gw *Gateway) write(_ ronykit.Conn, e ronykit.Envelope) error {
+    out := Envelope{
+        Hdr: map[string]string{},
+        Msg: e.GetMsg(),
+    }
+    e.WalkHdr(
+        func(key string, val string) bool {
+            out.Hdr[key] = val
+
+            return true
+        },
+    )
+    gw.req.out = append(gw.req.out, out)
+
+    return nil
+}
@@ -0,0 +1,81 @@
+package tracekittest
+
+import (
+    "context"
+    "sync"
+
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/trace"
+)
+
+// Recorder records the ended spans of its provider in memory. Spans are recorded
+// synchronously, hence they could be read as soon as they are ended.
+//
+//	rec := tracekittest.NewRecorder()
+//	h := tracekit.W3C("users", tracekit.WithTracerProvider(rec.TracerProvider()))
+type Recorder struct {
+    mtx   sync.Mutex
+    tp    *sdktrace.TracerProvider
+    spans []sdktrace.ReadOnlySpan
+}
+
+var _ sdktrace.SpanProcessor = (*Recorder)(nil)
+
+// NewRecorder returns a recorder with a provider which samples all the spans, unless their
+// parent is not sampled.
+func NewRecorder() *Recorder {
+    r := &Recorder{}
+    r.tp = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(r))
+
+    return r
+}
+
+// TracerProvider returns the provider which records its spans in the recorder.
+func (r *Recorder) TracerProvider() trace.TracerProvider {
+    return r.tp
+}
+
+// Spans returns the ended spans in the order they are ended.
+func (r *Recorder) Spans() []sdktrace.ReadOnlySpan {
+    r.mtx.Lock()
+    defer r.mtx.Unlock()
+
+    spans := make([]sdktrace.ReadOnlySpan, len(r.spans))
+    copy(spans, r.spans)
+
+    return spans
+}
+
+// Span returns the first ended span with the name, or nil if there is none.
+func (r *Recorder) Span(name string) sdktrace.ReadOnlySpan {
+    for _, s := range r.Spans() {
+        if s.Name() == name {
+            return s
+        }
+    }
+
+    return nil
+}
+
+// Reset removes the recorded spans.
+func (r *Recorder) Reset() {
+    r.mtx.Lock()
+    r.spans = nil
+    r.mtx.Unlock()
+}
+
+func (r *Recorder) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (r *Recorder) OnEnd(s sdktrace.ReadOnlySpan) {
+    r.mtx.Lock()
+    r.spans = append(r.spans, s)
+    r.mtx.Unlock()
+}
+
+func (r *Recorder) Shutdown(context.Context) error {
+    return nil
+}
+
+func (r *Recorder) ForceFlush(context.Context) error {
+    return nil
+}
@@ -0,0 +1,622 @@
+package swagger
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "os"
+    "sort"
+    "strings"
+    "text/template"
+
+    "github.com/clubpay/ronykit/desc"
+    "github.com/go-openapi/spec"
+    "github.com/go-openapi/swag"
+)
+
+// clientGen generates typed clients for the REST contracts of the services. It uses the
+// same descriptions which NewSwagger generates the document from, hence the generated
+// clients and the document always match.
+type clientGen struct {
+    pkgName string
+    tagName string
+}
+
+func NewClientGen(pkgName string) *clientGen {
+    return &clientGen{
+        pkgName: pkgName,
+        tagName: "json",
+    }
+}
+
+func (cg *clientGen) WithTag(tagName string) *clientGen {
+    cg.tagName = tagName
+
+    return cg
+}
+
+// goClientTag is the tag which ronykit.JSON encodes the messages with.
+const goClientTag = "json"
+
+func (cg clientGen) WriteGoToFile(filename string, services ...desc.ServiceDesc) error {
+    return writeToFile(filename, func(w io.Writer) error { return cg.WriteGoTo(w, services...) })
+}
+
+// WriteGoTo writes a Go client which uses ronykit's stub package to call the contracts.
+// The stub encodes the messages with ronykit.JSON, hence the client could only be generated
+// with the json tag.
+func (cg clientGen) WriteGoTo(w io.Writer, services ...desc.ServiceDesc) error {
+    if cg.tagName != goClientTag {
+        return fmt.Errorf("go client only supports the %q tag, got %q", goClientTag, cg.tagName)
+    }
+
+    doc, err := cg.spec(services...)
+    if err != nil {
+        return err
+    }
+
+    d, err := newGoGenDoc(cg.pkgName, cg.tagName, doc)
+    if err != nil {
+        return err
+    }
+
+    code, err := renderGo(goClientTemplate, d)
+    if err != nil {
+        return err
+    }
+
+    _, err = w.Write(code)
+
+    return err
+}
+
+func (cg clientGen) WriteTSToFile(filename string, services ...desc.ServiceDesc) error {
+    return writeToFile(filename, func(w io.Writer) error { return cg.WriteTSTo(w, services...) })
+}
+
+// WriteTSTo writes a TypeScript client which uses fetch API to call the contracts.
+func (cg clientGen) WriteTSTo(w io.Writer, services ...desc.ServiceDesc) error {
+    doc, err := cg.spec(services...)
+    if err != nil {
+        return err
+    }
+
+    buf := &bytes.Buffer{}
+    err = tsClientTemplate.Execute(buf, newTSDoc(doc))
+    if err != nil {
+        return err
+    }
+
+    _, err = w.Write(buf.Bytes())
+
+    return err
+}
+
+// internalDocVersion is the version of the documents which are only generated to render the
+// clients. It is not written to the output, but the documents are validated before rendering.
+const internalDocVersion = "internal"
+
+func (cg clientGen) spec(services ...desc.ServiceDesc) (*spec.Swagger, error) {
+    sg := NewSwagger(cg.pkgName, internalDocVersion, "").WithTag(cg.tagName)
+    if err := sg.build(services...); err != nil {
+        return nil, err
+    }
+
+    return sg.s, nil
+}
+
+func writeToFile(filename string, f func(w io.Writer) error) error {
+    file, err := os.Create(filename)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    return f(file)
+}
+
+// ErrorCodes returns the possible errors of the contract, one per status code.
+func (c goGenContract) ErrorCodes() []goGenError {
+    var out []goGenError
+    codes := map[int]struct{}{}
+    for _, e := range c.Errors {
+        if _, ok := codes[e.Code]; ok {
+            continue
+        }
+        codes[e.Code] = struct{}{}
+        out = append(out, e)
+    }
+
+    return out
+}
+
+var goClientTemplate = template.Must(template.New("goClient").Parse(`
+{{- if .Title }}// Code generated from "{{ .Title }}" by swagger.ClientGen. DO NOT EDIT.{{ end }}
+
+package {{ .Pkg }}
+
+import (
+    "context"
+    "encoding/json"
+    {{- if .HasErrors }}
+    "fmt"
+    {{- end }}
+    "net/http"
+
+    "github.com/clubpay/ronykit"
+    "github.com/clubpay/ronykit/stub"
+)
+` + goTypesTemplate + `
+
+{{ range $svc := .Services }}
+type {{ $svc.TypeName }}Client struct {
+    s *stub.Stub
+}
+
+func New{{ $svc.TypeName }}Client(hostPort string, opts ...stub.Option) *{{ $svc.TypeName }}Client {
+    return &{{ $svc.TypeName }}Client{
+        s: stub.New(hostPort, opts...),
+    }
+}
+{{ range $svc.Contracts }}
+func (c {{ $svc.TypeName }}Client) {{ .Handler }}(
+    ctx context.Context, req *{{ .Input }}, opt ...stub.RESTOption,
+) (*{{ .Output }}, error) {
+    res := {{ .OutputExpr }}
+    httpCtx := c.s.REST().
+        SetMethod(http.{{ .Method }}).
+        SetResponseHandler(
+            http.StatusOK,
+            func(ctx context.Context, r stub.RESTResponse) *stub.Error {
+                {{- if eq .Output "ronykit.RawMessage" }}
+                res.Copy(r.GetBody())
+
+                return nil
+                {{- else }}
+                return stub.WrapError(json.Unmarshal(r.GetBody(), res))
+                {{- end }}
+            },
+        ).
+        {{- range .ErrorCodes }}
+        SetResponseHandler(
+            {{ .Code }},
+            func(ctx context.Context, r stub.RESTResponse) *stub.Error {
+                errRes := &{{ .Type }}{}
+                err := json.Unmarshal(r.GetBody(), errRes)
+                if err != nil {
+                    return stub.WrapError(err)
+                }
+
+                return stub.NewErrorWithMsg(errRes)
+            },
+        ).
+        {{- end }}
+        DefaultResponseHandler(
+            func(ctx context.Context, r stub.RESTResponse) *stub.Error {
+                return stub.NewError(r.StatusCode(), string(r.GetBody()))
+            },
+        ).
+        AutoRun(ctx, {{ printf "%q" .Path }}, ronykit.JSON, req, opt...)
+    defer httpCtx.Release()
+
+    if err := httpCtx.Error(); err != nil {
+        return nil, err
+    }
+
+    return res, nil
+}
+{{ end }}
+{{ end }}
+`))
+
+type tsDoc struct {
+    Types    []tsType
+    Services []*tsService
+
+    defs spec.Definitions
+}
+
+type tsType struct {
+    Name   string
+    Fields []tsField
+}
+
+type tsField struct {
+    Name     string
+    Type     string
+    Optional bool
+}
+
+type tsService struct {
+    Name       string
+    ClassName  string
+    Operations []tsOperation
+}
+
+type tsOperation struct {
+    Name       string
+    Method     string
+    Path       string
+    Input      string
+    Output     string
+    ErrorType  string
+    QueryNames []string
+    QuerySeps  map[string]string
+    HasBody    bool
+}
+
+func newTSDoc(doc *spec.Swagger) *tsDoc {
+    d := &tsDoc{
+        defs: doc.Definitions,
+    }
+
+    defNames := make([]string, 0, len(doc.Definitions))
+    for name := range doc.Definitions {
+        defNames = append(defNames, name)
+    }
+    sort.Strings(defNames)
+    for _, name := range defNames {
+        d.Types = append(
+            d.Types,
+            tsType{
+                Name:   name,
+                Fields: tsFields(doc.Definitions[name]),
+            },
+        )
+    }
+
+    walkOperations(
+        doc,
+        func(method, path string, op *spec.Operation, commonParams []spec.Parameter) {
+            d.addOperation(method, path, op, append(append([]spec.Parameter{}, commonParams...), op.Parameters...))
+        },
+    )
+
+    return d
+}
+
+func (d *tsDoc) service(name string) *tsService {
+    for _, s := range d.Services {
+        if s.Name == name {
+            return s
+        }
+    }
+
+    className := swag.ToGoName(name)
+    if !strings.HasSuffix(strings.ToLower(className), "service") {
+        className += "Service"
+    }
+    s := &tsService{
+        Name:      name,
+        ClassName: className + "Client",
+    }
+    d.Services = append(d.Services, s)
+
+    return s
+}
+
+func (d *tsDoc) addOperation(method, path string, op *spec.Operation, params []spec.Parameter) {
+    serviceName := "default"
+    if len(op.Tags) > 0 {
+        serviceName = op.Tags[0]
+    }
+    svc := d.service(serviceName)
+
+    name := op.ID
+    if name == "" {
+        name = strings.ToLower(method) + path
+    }
+
+    tsOp := tsOperation{
+        Name:   swag.ToVarName(camelCase(name)),
+        Method: method,
+        Path:   path,
+        Input:  "Record<string, never>",
+        Output: "unknown",
+    }
+
+    var (
+        reqParams []tsField
+        errTypes  []string
+    )
+    for _, p := range params {
+        switch p.In {
+        case "body":
+            tsOp.Input = tsTypeOf(p.Schema)
+            tsOp.HasBody = true
+        case "query":
+            tsOp.QueryNames = append(tsOp.QueryNames, p.Name)
+            if sep, ok := collectionSeps[p.CollectionFormat]; ok && p.Type == "array" {
+                if tsOp.QuerySeps == nil {
+                    tsOp.QuerySeps = map[string]string{}
+                }
+                tsOp.QuerySeps[p.Name] = sep
+            }
+            reqParams = append(reqParams, tsField{Name: p.Name, Type: tsParamType(p), Optional: !p.Required})
+        case "path":
+            reqParams = append(reqParams, tsField{Name: p.Name, Type: tsParamType(p)})
+        }
+    }
+    if !tsOp.HasBody && len(reqParams) > 0 {
+        tsOp.Input = d.typeWithParams(reqParams)
+    }
+
+    if op.Responses != nil {
+        codes := make([]int, 0, len(op.Responses.StatusCodeResponses))
+        for code := range op.Responses.StatusCodeResponses {
+            codes = append(codes, code)
+        }
+        sort.Ints(codes)
+
+        for _, code := range codes {
+            res := op.Responses.StatusCodeResponses[code]
+            switch {
+            case code >= 200 && code < 300 && tsOp.Output == "unknown":
+                tsOp.Output = tsTypeOf(res.Schema)
+            case code >= 400 && res.Schema != nil:
+                t := tsTypeOf(res.Schema)
+                if !swag.ContainsStrings(errTypes, t) {
+                    errTypes = append(errTypes, t)
+                }
+            }
+        }
+    }
+    tsOp.ErrorType = "unknown"
+    if len(errTypes) > 0 {
+        tsOp.ErrorType = strings.Join(errTypes, " | ")
+    }
+
+    svc.Operations = append(svc.Operations, tsOp)
+}
+
+// typeWithParams returns the definition which has exactly the same properties as
+// the params, or an inline type if there is no such definition.
+func (d *tsDoc) typeWithParams(params []tsField) string {
+    names := make([]string, 0, len(d.defs))
+    for name := range d.defs {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+
+    for _, name := range names {
+        def := d.defs[name]
+        if len(def.Properties) != len(params) {
+            continue
+        }
+
+        found := true
+        for _, p := range params {
+            _, ok := def.Properties[p.Name]
+            found = found && ok
+        }
+        if found {
+            return name
+        }
+    }
+
+    return tsInline(params)
+}
+
+// TSPath returns the TypeScript template literal which builds the path of the operation.
+func (op tsOperation) TSPath() string {
+    sb := strings.Builder{}
+    sb.WriteRune('`')
+    for idx, p := range strings.Split(op.Path, "/") {
+        if idx > 0 {
+            sb.WriteRune('/')
+        }
+        if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+            sb.WriteString(fmt.Sprintf("${encodeURIComponent(String(req[%q]))}", p[1:len(p)-1]))
+        } else {
+            sb.WriteString(p)
+        }
+    }
+    sb.WriteRune('`')
+
+    return sb.String()
+}
+
+// collectionSeps holds the separator of the array params for each collection format. Params with
+// the multi format are repeated instead.
+var collectionSeps = map[string]string{
+    "":      ",",
+    "csv":   ",",
+    "ssv":   " ",
+    "tsv":   "\t",
+    "pipes": "|",
+}
+
+// TSQuery returns the TypeScript object literal which holds the query params of the operation.
+// Array params are joined based on their collection format.
+func (op tsOperation) TSQuery() string {
+    if len(op.QueryNames) == 0 {
+        return "{}"
+    }
+
+    items := make([]string, 0, len(op.QueryNames))
+    for _, n := range op.QueryNames {
+        if sep, ok := op.QuerySeps[n]; ok {
+            items = append(items, fmt.Sprintf("%q: req[%q]?.join(%q)", n, n, sep))
+
+            continue
+        }
+        items = append(items, fmt.Sprintf("%q: req[%q]", n, n))
+    }
+
+    return fmt.Sprintf("{ %s }", strings.Join(items, ", "))
+}
+
+func tsFields(s spec.Schema) []tsField {
+    names := make([]string, 0, len(s.Properties))
+    for n := range s.Properties {
+        names = append(names, n)
+    }
+    sort.Strings(names)
+
+    fields := make([]tsField, 0, len(names))
+    for _, n := range names {
+        ps := s.Properties[n]
+        fields = append(
+            fields,
+            tsField{
+                Name:     n,
+                Type:     tsTypeOf(&ps),
+                Optional: len(s.Required) > 0 && !swag.ContainsStrings(s.Required, n),
+            },
+        )
+    }
+
+    return fields
+}
+
+func tsInline(fields []tsField) string {
+    sb := strings.Builder{}
+    sb.WriteString("{ ")
+    for _, f := range fields {
+        sb.WriteString(fmt.Sprintf("%q", f.Name))
+        if f.Optional {
+            sb.WriteRune('?')
+        }
+        sb.WriteString(": ")
+        sb.WriteString(f.Type)
+        sb.WriteString("; ")
+    }
+    sb.WriteRune('}')
+
+    return sb.String()
+}
+
+func tsTypeOf(s *spec.Schema) string {
+    if s == nil {
+        return "unknown"
+    }
+    if _, ok := s.Extensions[oneOfExtension]; ok {
+        schemas := oneOfSchemas(s)
+        types := make([]string, 0, len(schemas))
+        for idx := range schemas {
+            types = append(types, tsTypeOf(&schemas[idx]))
+        }
+
+        return strings.Join(types, " | ")
+    }
+    if ref := refName(s); ref != "" {
+        return ref
+    }
+    if len(s.Enum) > 0 {
+        values := make([]string, 0, len(s.Enum))
+        for _, v := range s.Enum {
+            values = append(values, fmt.Sprintf("%q", fmt.Sprint(v)))
+            if !s.Type.Contains("string") {
+                values[len(values)-1] = fmt.Sprint(v)
+            }
+        }
+
+        return strings.Join(values, " | ")
+    }
+
+    switch {
+    case s.Type.Contains("array"):
+        if s.Items == nil || s.Items.Schema == nil {
+            return "unknown[]"
+        }
+
+        return fmt.Sprintf("Array<%s>", tsTypeOf(s.Items.Schema))
+    case s.Type.Contains("string"):
+        return "string"
+    case s.Type.Contains("integer"), s.Type.Contains("number"):
+        return "number"
+    case s.Type.Contains("boolean"):
+        return "boolean"
+    case len(s.Properties) > 0:
+        return tsInline(tsFields(*s))
+    case s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+        return fmt.Sprintf("Record<string, %s>", tsTypeOf(s.AdditionalProperties.Schema))
+    case s.Type.Contains("object"):
+        return "Record<string, unknown>"
+    }
+
+    return "unknown"
+}
+
+func tsParamType(p spec.Parameter) string {
+    return tsTypeOf(paramSchema(p))
+}
+
+var tsClientTemplate = template.Must(template.New("tsClient").Parse(`// Code generated by swagger.ClientGen. DO NOT EDIT.
+
+export class ApiError<T = unknown> extends Error {
+  constructor(readonly status: number, readonly body: T) {
+    super(` + "`request failed with status ${status}`" + `);
+  }
+}
+
+type Query = Record<string, unknown>;
+
+async function request<T>(
+  baseUrl: string,
+  init: RequestInit,
+  method: string,
+  path: string,
+  query: Query,
+  body?: unknown,
+): Promise<T> {
+  // new URL(path, baseUrl) would drop the path of baseUrl, since path is absolute.
+  const base = new URL(baseUrl);
+  const url = new URL(base.pathname.replace(/\/+$/, "") + path, base);
+  for (const [key, value] of Object.entries(query)) {
+    if (value === undefined || value === null) {
+      continue;
+    }
+    for (const v of Array.isArray(value) ? value : [value]) {
+      url.searchParams.append(key, String(v));
+    }
+  }
+
+  const headers = new Headers(init.headers);
+  headers.set("Content-Type", "application/json");
+  const res = await fetch(url.toString(), {
+    ...init,
+    method,
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  const text = await res.text();
+  const data = text === "" ? undefined : JSON.parse(text);
+  if (!res.ok) {
+    throw new ApiError(res.status, data);
+  }
+
+  return data as T;
+}
+{{ range .Types }}
+export interface {{ .Name }} {
+{{- range .Fields }}
+  {{ printf "%q" .Name }}{{ if .Optional }}?{{ end }}: {{ .Type }};
+{{- end }}
+}
+{{ end }}
+{{- range .Services }}
+export class {{ .ClassName }} {
+  constructor(private readonly baseUrl: string, private readonly init: RequestInit = {}) {}
+{{ range .Operations }}
+  /**
+   * {{ .Method }} {{ .Path }}
+   *
+   * @throws ApiError<{{ .ErrorType }}>
+   */
+  {{ .Name }}(req: {{ .Input }}): Promise<{{ .Output }}> {
+    return request<{{ .Output }}>(
+      this.baseUrl,
+      this.init,
+      {{ printf "%q" .Method }},
+      {{ .TSPath }},
+      {{ .TSQuery }},
+      {{- if .HasBody }}
+      req,
+      {{- end }}
+    );
+  }
+{{ end -}}
+}
+{{ end -}}
+`))
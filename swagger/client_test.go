@@ -0,0 +1,116 @@
+package swagger_test
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/swagger"
+    "github.com/clubpay/ronykit"
+    "github.com/clubpay/ronykit/desc"
+    "github.com/clubpay/ronykit/std/gateway/fasthttp"
+)
+
+func TestClientGen(t *testing.T) {
+    cg := swagger.NewClientGen("testclient")
+
+    goCode := &strings.Builder{}
+    err := cg.WriteGoTo(goCode, testService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    vetGo(t, goCode.String())
+
+    for _, expected := range []string{
+        "func NewTestServiceClient(hostPort string, opts ...stub.Option) *TestServiceClient",
+        "func (c TestServiceClient) GetSomeXY(\n\tctx context.Context, req *sampleReq, opt ...stub.RESTOption,\n) (*sampleRes, error)",
+        `AutoRun(ctx, "/some/:x/:y", ronykit.JSON, req, opt...)`,
+        "errRes := &sampleError{}",
+    } {
+        if !strings.Contains(goCode.String(), expected) {
+            t.Errorf("expected %q in generated code:\n%s", expected, goCode.String())
+        }
+    }
+
+    tsCode := &strings.Builder{}
+    err = cg.WriteTSTo(tsCode, testService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    for _, expected := range []string{
+        "export interface sampleReq {",
+        `"w": Array<string>;`,
+        "export class TestServiceClient {",
+        "getSomeXY(req: sampleReq): Promise<sampleRes> {",
+        "postSomeXY(req: sampleReq): Promise<anotherRes> {",
+        "`/some/${encodeURIComponent(String(req[\"x\"]))}/${encodeURIComponent(String(req[\"y\"]))}`",
+        "@throws ApiError<sampleError>",
+        `new URL(base.pathname.replace(/\/+$/, "") + path, base)`,
+    } {
+        if !strings.Contains(tsCode.String(), expected) {
+            t.Errorf("expected %q in generated code:\n%s", expected, tsCode.String())
+        }
+    }
+}
+
+func TestClientGenTag(t *testing.T) {
+    // The Go client encodes the messages with ronykit.JSON, so the types must have json tags.
+    err := swagger.NewClientGen("testclient").
+        WithTag("msgpack").
+        WriteGoTo(&strings.Builder{}, testService{})
+    if err == nil || !strings.Contains(err.Error(), `only supports the "json" tag`) {
+        t.Errorf("expected tag error, got: %v", err)
+    }
+
+    err = swagger.NewClientGen("testclient").
+        WithTag("msgpack").
+        WriteTSTo(&strings.Builder{}, testService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+type rawService struct{}
+
+func (rawService) Desc() *desc.Service {
+    return desc.NewService("rawService").
+        AddContract(
+            desc.NewContract().
+                SetName("getRaw").
+                AddSelector(fasthttp.GET("/raw")).
+                SetInput(&nestedItem{}).
+                SetOutput(&ronykit.RawMessage{}),
+            desc.NewContract().
+                SetName("listRaw").
+                AddSelector(fasthttp.GET("/raw/list")).
+                SetInput(&nestedItem{}).
+                SetOutput(&rawOutput{}),
+            desc.NewContract().
+                SetName("putRaw").
+                AddSelector(fasthttp.PUT("/raw")).
+                SetInput(&ronykit.RawMessage{}).
+                SetOutput(&ronykit.RawMessage{}),
+        )
+}
+
+func TestClientGenRawMessages(t *testing.T) {
+    goCode := &strings.Builder{}
+    err := swagger.NewClientGen("rawclient").WriteGoTo(goCode, rawService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    vetGo(t, goCode.String())
+
+    for _, expected := range []string{
+        ") (*ronykit.RawMessage, error)",
+        "res.Copy(r.GetBody())",
+        "type ListRawResponse []string",
+        ") (*ListRawResponse, error)",
+    } {
+        if !strings.Contains(goCode.String(), expected) {
+            t.Errorf("expected %q in generated code:\n%s", expected, goCode.String())
+        }
+    }
+}
@@ -0,0 +1,147 @@
+package swagger
+
+import (
+    "encoding/json"
+    "fmt"
+    "reflect"
+    "sort"
+
+    "github.com/clubpay/ronykit"
+    "github.com/clubpay/ronykit/desc"
+    "github.com/go-openapi/spec"
+    "github.com/go-openapi/swag"
+)
+
+const oneOfExtension = "x-oneOf"
+
+// errorResponse holds all the possible errors of an operation which share the same status code.
+type errorResponse struct {
+    types   []string
+    items   []string
+    example ronykit.Message
+}
+
+func (er *errorResponse) add(typeName, item string) {
+    if !swag.ContainsStrings(er.types, typeName) {
+        er.types = append(er.types, typeName)
+    }
+    er.items = append(er.items, item)
+}
+
+// schema returns a reference to the error type, or if there are more than one error types
+// an object which lists all of them in x-oneOf. Swagger 2.0 does not support oneOf, hence we
+// use the vendor extension.
+func (er errorResponse) schema() *spec.Schema {
+    if len(er.types) == 1 {
+        return spec.RefProperty(fmt.Sprintf("#/definitions/%s", er.types[0]))
+    }
+
+    refs := make([]spec.Schema, 0, len(er.types))
+    for _, t := range er.types {
+        refs = append(refs, *spec.RefProperty(fmt.Sprintf("#/definitions/%s", t)))
+    }
+
+    s := &spec.Schema{}
+    s.Typed("object", "")
+    // AddExtension lowercases the key, so we set it directly to keep the conventional name.
+    s.Extensions = spec.Extensions{oneOfExtension: refs}
+
+    return s
+}
+
+// oneOfSchemas returns the schemas listed in x-oneOf of s. If s does not have x-oneOf, s
+// itself is returned.
+func oneOfSchemas(s *spec.Schema) []spec.Schema {
+    if s == nil {
+        return nil
+    }
+
+    ext, ok := s.Extensions[oneOfExtension]
+    if !ok {
+        return []spec.Schema{*s}
+    }
+
+    // The extension could be already parsed from a JSON document or set by ourselves,
+    // JSON round-trip handles both.
+    var schemas []spec.Schema
+    data, _ := json.Marshal(ext)
+    _ = json.Unmarshal(data, &schemas)
+
+    return schemas
+}
+
+// addErrorItem records the item of the error, to be listed as the enum of the item field of the
+// error's definition.
+func (sg swaggerGen) addErrorItem(pe desc.Error) {
+    if pe.Item == "" {
+        return
+    }
+
+    v := reflect.Indirect(reflect.ValueOf(pe.Message))
+    if v.Kind() != reflect.Struct {
+        return
+    }
+
+    for i := 0; i < v.NumField(); i++ {
+        f := v.Type().Field(i)
+        if f.Type.Kind() != reflect.String || v.Field(i).String() != pe.Item {
+            continue
+        }
+
+        pt, ok := parseField(f, sg.tagName)
+        if !ok {
+            continue
+        }
+
+        fields, ok := sg.errItems[v.Type().Name()]
+        if !ok {
+            fields = map[string][]string{}
+            sg.errItems[v.Type().Name()] = fields
+        }
+        if !swag.ContainsStrings(fields[pt.Name], pe.Item) {
+            fields[pt.Name] = append(fields[pt.Name], pe.Item)
+        }
+
+        return
+    }
+}
+
+// setErrorItems sets the recorded items of the errors as the enum of their item field.
+func (sg swaggerGen) setErrorItems(doc *spec.Swagger) {
+    for defName, fields := range sg.errItems {
+        def, ok := doc.Definitions[defName]
+        if !ok {
+            continue
+        }
+
+        for fieldName, items := range fields {
+            prop, ok := def.Properties[fieldName]
+            if !ok {
+                continue
+            }
+
+            // The items are merged with the enum of the field, which might be set by its tag.
+            // The enum is copied, since the schema of the property could share it with others.
+            sort.Strings(items)
+            enum := append([]interface{}{}, prop.Enum...)
+            for _, item := range items {
+                if !containsValue(enum, item) {
+                    enum = append(enum, item)
+                }
+            }
+            prop.Enum = enum
+            def.Properties[fieldName] = prop
+        }
+        doc.Definitions[defName] = def
+    }
+}
+
+func containsValue(values []interface{}, v interface{}) bool {
+    for _, value := range values {
+        if value == v {
+            return true
+        }
+    }
+
+    return false
+}
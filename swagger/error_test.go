@@ -0,0 +1,153 @@
+package swagger_test
+
+import (
+    "fmt"
+    "strings"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/swagger"
+    "github.com/clubpay/ronykit/desc"
+    "github.com/clubpay/ronykit/std/gateway/fasthttp"
+    "github.com/goccy/go-json"
+)
+
+type validationError struct {
+    Code  int    `json:"code"`
+    Item  string `json:"item"`
+    Field string `json:"field"`
+}
+
+func (e validationError) GetCode() int {
+    return e.Code
+}
+
+func (e validationError) GetItem() string {
+    return e.Item
+}
+
+func (e validationError) Error() string {
+    return fmt.Sprintf("%d: %s", e.Code, e.Item)
+}
+
+type errorService struct{}
+
+func (errorService) Desc() *desc.Service {
+    return desc.NewService("errorService").
+        AddContract(
+            desc.NewContract().
+                AddSelector(fasthttp.GET("/items/:x")).
+                SetInput(&sampleReq{}).
+                SetOutput(&sampleRes{}).
+                AddError(&sampleError{400, "BAD_REQUEST"}).
+                AddError(&validationError{Code: 400, Item: "INVALID_X"}).
+                AddError(&validationError{Code: 400, Item: "INVALID_Y"}),
+        )
+}
+
+func TestErrorResponses(t *testing.T) {
+    sb := &strings.Builder{}
+    err := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WriteTo(sb, errorService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    doc := struct {
+        Definitions map[string]struct {
+            Properties map[string]struct {
+                Enum []interface{} `json:"enum"`
+            } `json:"properties"`
+        } `json:"definitions"`
+        Paths map[string]map[string]struct {
+            Responses map[string]struct {
+                Description string `json:"description"`
+                Schema      struct {
+                    OneOf []struct {
+                        Ref string `json:"$ref"`
+                    } `json:"x-oneOf"`
+                } `json:"schema"`
+            } `json:"responses"`
+        } `json:"paths"`
+    }{}
+    err = json.Unmarshal([]byte(sb.String()), &doc)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    res := doc.Paths["/items/{x}"]["get"].Responses["400"]
+    if len(res.Schema.OneOf) != 2 ||
+        res.Schema.OneOf[0].Ref != "#/definitions/sampleError" ||
+        res.Schema.OneOf[1].Ref != "#/definitions/validationError" {
+        t.Errorf("expected both error types in x-oneOf: %v", res.Schema.OneOf)
+    }
+    if res.Description != "Items: BAD_REQUEST, INVALID_X, INVALID_Y" {
+        t.Errorf("unexpected description: %s", res.Description)
+    }
+
+    enum := doc.Definitions["validationError"].Properties["item"].Enum
+    if fmt.Sprint(enum) != "[INVALID_X INVALID_Y]" {
+        t.Errorf("unexpected item enum: %v", enum)
+    }
+    // The enum of the code is typed as its integer schema.
+    if code := doc.Definitions["sampleError"].Properties["code"].Enum; fmt.Sprint(code) != "[504 503]" {
+        t.Errorf("unexpected code enum: %#v", code)
+    } else if _, ok := code[0].(float64); !ok {
+        t.Errorf("code enum must be numbers: %#v", code)
+    }
+
+    code := &strings.Builder{}
+    err = swagger.NewGoGen("api").WriteTo(code, []byte(sb.String()))
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, expected := range []string{
+        `AddError(&validationError{Code: 400, Item: "INVALID_X"})`,
+        `AddError(&validationError{Code: 400, Item: "INVALID_Y"})`,
+        `AddError(&sampleError{Code: 400, Item: "BAD_REQUEST"})`,
+    } {
+        if !strings.Contains(code.String(), expected) {
+            t.Errorf("expected %q in generated code:\n%s", expected, code.String())
+        }
+    }
+}
+
+type taggedError struct {
+    Code int    `json:"code"`
+    Item string `json:"item" swag:"enum:NOT_FOUND,INVALID_X"`
+}
+
+func (e taggedError) GetCode() int {
+    return e.Code
+}
+
+func (e taggedError) GetItem() string {
+    return e.Item
+}
+
+func (e taggedError) Error() string {
+    return fmt.Sprintf("%d: %s", e.Code, e.Item)
+}
+
+type taggedErrorService struct{}
+
+func (taggedErrorService) Desc() *desc.Service {
+    return desc.NewService("taggedErrorService").
+        AddContract(
+            desc.NewContract().
+                AddSelector(fasthttp.GET("/tagged/:x")).
+                SetInput(&sampleReq{}).
+                SetOutput(&sampleRes{}).
+                AddError(&taggedError{Code: 400, Item: "INVALID_Y"}).
+                AddError(&taggedError{Code: 400, Item: "INVALID_X"}),
+        )
+}
+
+func TestErrorItemsEnum(t *testing.T) {
+    // The items are merged with the enum of the tag, without duplicates.
+    doc := generate(t, taggedErrorService{})
+    enum := doc.Definitions["taggedError"].Properties["item"].Enum
+    if fmt.Sprint(enum) != "[NOT_FOUND INVALID_X INVALID_Y]" {
+        t.Errorf("unexpected item enum: %v", enum)
+    }
+}
@@ -0,0 +1,210 @@
+package swagger
+
+import (
+    "encoding/json"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/clubpay/ronykit"
+    "github.com/go-openapi/spec"
+)
+
+const (
+    examplesExtension = "x-examples"
+    exampleExtension  = "x-example"
+    jsonMediaType     = "application/json"
+)
+
+type contractExample struct {
+    in  ronykit.Message
+    out ronykit.Message
+}
+
+// setOperationExamples sets the registered examples of the contract to the operation. The input
+// example goes to the x-examples of the body param, or x-example of each non-body param,
+// and the output example goes to the examples of the successful response.
+func setOperationExamples(op *spec.Operation, ex contractExample) {
+    if ex.out != nil && op.Responses != nil {
+        if res, ok := op.Responses.StatusCodeResponses[200]; ok {
+            op.Responses.StatusCodeResponses[200] = *res.AddExample(jsonMediaType, marshalExample(ex.out))
+        }
+    }
+
+    if ex.in == nil {
+        return
+    }
+
+    in := marshalExample(ex.in)
+    fields, _ := in.(map[string]interface{})
+    for idx := range op.Parameters {
+        p := &op.Parameters[idx]
+        switch p.In {
+        case "body":
+            p.AddExtension(examplesExtension, map[string]interface{}{jsonMediaType: in})
+        default:
+            if v, ok := fieldExample(fields, p.Name); ok {
+                p.AddExtension(exampleExtension, v)
+            }
+        }
+    }
+}
+
+// fieldExample returns the value of the field with the given name from the example. Names of the
+// struct query params are dotted, e.g. filter.status, hence they are looked up in nested objects.
+func fieldExample(fields map[string]interface{}, name string) (interface{}, bool) {
+    if v, ok := fields[name]; ok {
+        return v, true
+    }
+
+    parts := strings.SplitN(name, ".", 2)
+    if len(parts) != 2 {
+        return nil, false
+    }
+    sub, ok := fields[parts[0]].(map[string]interface{})
+    if !ok {
+        return nil, false
+    }
+
+    return fieldExample(sub, parts[1])
+}
+
+// addDefinitionExamples sets an example for every definition, which is built from the
+// examples of its properties.
+func addDefinitionExamples(swag *spec.Swagger) {
+    names := make([]string, 0, len(swag.Definitions))
+    for name := range swag.Definitions {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+
+    for _, name := range names {
+        def := swag.Definitions[name]
+        if def.Example != nil {
+            continue
+        }
+
+        def.Example = exampleOf(&def, swag.Definitions, map[string]bool{name: true})
+        swag.Definitions[name] = def
+    }
+}
+
+// marshalExample converts m to its JSON representation, so the example in the document
+// matches what is sent over the wire.
+func marshalExample(m ronykit.Message) interface{} {
+    data, err := json.Marshal(m)
+    if err != nil {
+        return nil
+    }
+
+    var ex interface{}
+    _ = json.Unmarshal(data, &ex)
+
+    return ex
+}
+
+// typedExample converts the raw example value, which is set in the swag tag, based on the
+// type of the schema. For arrays, raw is a comma separated list of the items.
+func typedExample(raw string, s *spec.Schema) interface{} {
+    switch {
+    case s.Type.Contains("array"):
+        items := make([]interface{}, 0)
+        for _, v := range strings.Split(raw, swagValueSep) {
+            if s.Items != nil && s.Items.Schema != nil {
+                items = append(items, typedExample(strings.TrimSpace(v), s.Items.Schema))
+            } else {
+                items = append(items, strings.TrimSpace(v))
+            }
+        }
+
+        return items
+    case s.Type.Contains("integer"):
+        if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+            return v
+        }
+    case s.Type.Contains("number"):
+        if v, err := strconv.ParseFloat(raw, 64); err == nil {
+            return v
+        }
+    case s.Type.Contains("boolean"):
+        if v, err := strconv.ParseBool(raw); err == nil {
+            return v
+        }
+    }
+
+    return raw
+}
+
+// exampleOf builds an example value for the schema s. References are resolved from defs,
+// and each definition is expanded only once in every branch to avoid infinite recursion.
+func exampleOf(s *spec.Schema, defs spec.Definitions, visited map[string]bool) interface{} {
+    if s == nil {
+        return nil
+    }
+    if s.Example != nil {
+        return s.Example
+    }
+    if _, ok := s.Extensions[oneOfExtension]; ok {
+        schemas := oneOfSchemas(s)
+        if len(schemas) == 0 {
+            return nil
+        }
+
+        return exampleOf(&schemas[0], defs, visited)
+    }
+    if ref := refName(s); ref != "" {
+        def, ok := defs[ref]
+        if !ok || visited[ref] {
+            return nil
+        }
+
+        visited[ref] = true
+        ex := exampleOf(&def, defs, visited)
+        delete(visited, ref)
+
+        return ex
+    }
+    if len(s.Enum) > 0 {
+        // Enums which are not set by the swag tags, could still list the values as strings.
+        if raw, ok := s.Enum[0].(string); ok {
+            return typedExample(raw, s)
+        }
+
+        return s.Enum[0]
+    }
+
+    switch {
+    case s.Type.Contains("array"):
+        if s.Items == nil || s.Items.Schema == nil {
+            return []interface{}{}
+        }
+
+        return []interface{}{exampleOf(s.Items.Schema, defs, visited)}
+    case s.Type.Contains("string"):
+        switch s.Format {
+        case "date-time":
+            return "2006-01-02T15:04:05Z"
+        case "date":
+            return "2006-01-02"
+        }
+
+        return "string"
+    case s.Type.Contains("integer"):
+        return 0
+    case s.Type.Contains("number"):
+        return 0.0
+    case s.Type.Contains("boolean"):
+        return false
+    }
+
+    obj := map[string]interface{}{}
+    if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+        obj["key"] = exampleOf(s.AdditionalProperties.Schema, defs, visited)
+    }
+    for name, ps := range s.Properties {
+        ps := ps
+        obj[name] = exampleOf(&ps, defs, visited)
+    }
+
+    return obj
+}
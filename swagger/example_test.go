@@ -0,0 +1,146 @@
+package swagger_test
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/swagger"
+    "github.com/clubpay/ronykit/desc"
+    "github.com/clubpay/ronykit/std/gateway/fasthttp"
+    "github.com/goccy/go-json"
+)
+
+type exampleReq struct {
+    ID     int64    `json:"id" swag:"example:42"`
+    Kind   string   `json:"kind" swag:"enum:cat,dog"`
+    Labels []string `json:"labels" swag:"example:a,b"`
+}
+
+type exampleRes struct {
+    Name   string    `json:"name"`
+    Code   int       `json:"code" swag:"enum:504,503"`
+    Ratios []float64 `json:"ratios" swag:"enum:0.5,1"`
+}
+
+type exampleService struct{}
+
+func (exampleService) Desc() *desc.Service {
+    return desc.NewService("exampleService").
+        AddContract(
+            desc.NewContract().
+                SetName("createPet").
+                AddSelector(fasthttp.POST("/pets")).
+                SetInput(&exampleReq{}).
+                SetOutput(&exampleRes{}),
+        )
+}
+
+func TestExamples(t *testing.T) {
+    sb := &strings.Builder{}
+    err := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WithExample("exampleService", "createPet", &exampleReq{ID: 7, Kind: "dog"}, &exampleRes{Name: "Rex"}).
+        WriteTo(sb, exampleService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    doc := struct {
+        Definitions map[string]struct {
+            Example map[string]interface{} `json:"example"`
+        } `json:"definitions"`
+        Paths map[string]map[string]struct {
+            Parameters []struct {
+                In       string                            `json:"in"`
+                Examples map[string]map[string]interface{} `json:"x-examples"`
+            } `json:"parameters"`
+            Responses map[string]struct {
+                Examples map[string]map[string]interface{} `json:"examples"`
+            } `json:"responses"`
+        } `json:"paths"`
+    }{}
+    err = json.Unmarshal([]byte(sb.String()), &doc)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    reqEx := doc.Definitions["exampleReq"].Example
+    if reqEx["id"] != 42.0 || reqEx["kind"] != "cat" || len(reqEx["labels"].([]interface{})) != 2 {
+        t.Errorf("unexpected definition example: %v", reqEx)
+    }
+    if doc.Definitions["exampleRes"].Example["name"] != "string" {
+        t.Errorf("unexpected definition example: %v", doc.Definitions["exampleRes"].Example)
+    }
+
+    op := doc.Paths["/pets"]["post"]
+    for _, p := range op.Parameters {
+        if p.In == "body" && p.Examples["application/json"]["id"] != 7.0 {
+            t.Errorf("unexpected body example: %v", p.Examples)
+        }
+    }
+    if op.Responses["200"].Examples["application/json"]["name"] != "Rex" {
+        t.Errorf("unexpected response example: %v", op.Responses["200"].Examples)
+    }
+}
+
+type unnamedExampleService struct{}
+
+func (unnamedExampleService) Desc() *desc.Service {
+    return desc.NewService("unnamedExampleService").
+        AddContract(
+            desc.NewContract().
+                AddSelector(fasthttp.POST("/v2/pets")).
+                SetInput(&exampleReq{}).
+                SetOutput(&exampleRes{}),
+        )
+}
+
+func TestExampleContracts(t *testing.T) {
+    sg := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WithExample("exampleService", "createPet", nil, &exampleRes{Name: "Rex"}).
+        WithExample("unnamedExampleService", "0", nil, &exampleRes{Name: "Max"})
+
+    // Examples are keyed by the service, and unnamed contracts by their index.
+    doc := generateWith(t, sg, exampleService{}, unnamedExampleService{})
+    for path, name := range map[string]string{"/pets": "Rex", "/v2/pets": "Max"} {
+        ex := doc.Paths.Paths[path].Post.Responses.StatusCodeResponses[200].Examples["application/json"]
+        if ex.(map[string]interface{})["name"] != name {
+            t.Errorf("unexpected example of %s: %v", path, ex)
+        }
+    }
+
+    err := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WithExample("unnamedExampleService", "createPet", nil, &exampleRes{Name: "Rex"}).
+        WriteTo(&strings.Builder{}, exampleService{}, unnamedExampleService{})
+    if err == nil || !strings.Contains(err.Error(), "examples of unknown contracts: unnamedExampleService.createPet") {
+        t.Errorf("expected unknown contract error, got: %v", err)
+    }
+}
+
+func TestTypedEnums(t *testing.T) {
+    doc := generate(t, exampleService{})
+
+    def := doc.Definitions["exampleRes"]
+    if enum := def.Properties["code"].Enum; len(enum) != 2 || enum[0] != 504.0 || enum[1] != 503.0 {
+        t.Errorf("unexpected code enum: %#v", enum)
+    }
+    if enum := def.Properties["ratios"].Items.Schema.Enum; len(enum) != 2 || enum[0] != 0.5 {
+        t.Errorf("unexpected ratios enum: %#v", enum)
+    }
+    if enum := def.Properties["name"].Enum; len(enum) != 0 {
+        t.Errorf("unexpected name enum: %#v", enum)
+    }
+
+    ex, ok := def.Example.(map[string]interface{})
+    if !ok {
+        t.Fatalf("unexpected example: %v", def.Example)
+    }
+    if ex["code"] != 504.0 {
+        t.Errorf("unexpected code example: %#v", ex["code"])
+    }
+    if ratios, ok := ex["ratios"].([]interface{}); !ok || len(ratios) != 1 || ratios[0] != 0.5 {
+        t.Errorf("unexpected ratios example: %#v", ex["ratios"])
+    }
+}
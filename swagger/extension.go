@@ -0,0 +1,106 @@
+package swagger
+
+import (
+    "encoding/json"
+    "strings"
+
+    "github.com/go-openapi/spec"
+)
+
+const extensionPrefix = "x-"
+
+// extensions holds the vendor extensions which are set from code, and are added to the
+// document while the services are added.
+type extensions struct {
+    doc   spec.Extensions
+    tags  map[string]spec.Extensions
+    ops   map[contractKey]spec.Extensions
+    props map[string]map[string]spec.Extensions
+}
+
+func newExtensions() extensions {
+    return extensions{
+        doc:   spec.Extensions{},
+        tags:  map[string]spec.Extensions{},
+        ops:   map[contractKey]spec.Extensions{},
+        props: map[string]map[string]spec.Extensions{},
+    }
+}
+
+// WithExtension adds the vendor extension to the document. The x- prefix is added to name if
+// it does not have it.
+func (sg *swaggerGen) WithExtension(name string, value interface{}) *swaggerGen {
+    setExtension(&sg.ext.doc, name, value)
+
+    return sg
+}
+
+// WithTagExtension adds the vendor extension to the tag of the service.
+func (sg *swaggerGen) WithTagExtension(serviceName, name string, value interface{}) *swaggerGen {
+    ext := sg.ext.tags[serviceName]
+    setExtension(&ext, name, value)
+    sg.ext.tags[serviceName] = ext
+
+    return sg
+}
+
+// WithOperationExtension adds the vendor extension to the operations of the contract with the
+// name contractName in the service. Unnamed contracts are named by their index in the service,
+// as in WithExample.
+func (sg *swaggerGen) WithOperationExtension(
+    serviceName, contractName, name string, value interface{},
+) *swaggerGen {
+    key := contractKey{service: serviceName, contract: contractName}
+    ext := sg.ext.ops[key]
+    setExtension(&ext, name, value)
+    sg.ext.ops[key] = ext
+
+    return sg
+}
+
+// WithPropertyExtension adds the vendor extension to the property of the definition with the
+// name typeName. Extensions could also be set by the swag tag of the field, e.g.
+// `swag:"x-internal:true"`.
+func (sg *swaggerGen) WithPropertyExtension(typeName, property, name string, value interface{}) *swaggerGen {
+    props, ok := sg.ext.props[typeName]
+    if !ok {
+        props = map[string]spec.Extensions{}
+        sg.ext.props[typeName] = props
+    }
+    ext := props[property]
+    setExtension(&ext, name, value)
+    props[property] = ext
+
+    return sg
+}
+
+// setExtension sets the extension without changing the case of its name, since
+// spec.Extensions.Add lowercases it.
+func setExtension(ext *spec.Extensions, name string, value interface{}) {
+    if !strings.HasPrefix(strings.ToLower(name), extensionPrefix) {
+        name = extensionPrefix + name
+    }
+    if *ext == nil {
+        *ext = spec.Extensions{}
+    }
+
+    (*ext)[name] = value
+}
+
+// addExtensions copies all the extensions from src to dst.
+func addExtensions(dst *spec.Extensions, src spec.Extensions) {
+    for name, value := range src {
+        setExtension(dst, name, value)
+    }
+}
+
+// extensionValue parses the raw value of an extension which is set in the swag tag. JSON
+// values like numbers, booleans, arrays and objects are kept typed, otherwise it is a string.
+func extensionValue(raw string) interface{} {
+    var v interface{}
+    if err := json.Unmarshal([]byte(raw), &v); err != nil {
+        return raw
+    }
+
+    return v
+}
@@ -0,0 +1,87 @@
+package swagger_test
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/swagger"
+    "github.com/clubpay/ronykit/desc"
+    "github.com/clubpay/ronykit/std/gateway/fasthttp"
+)
+
+type extReq struct {
+    ID     string `json:"id" swag:"x-internal:true"`
+    Amount int64  `json:"amount" swag:"x-rate-limit:100;optional"`
+    Note   string `json:"note" swag:"x-Display-Name:Note"`
+}
+
+type extService struct{}
+
+func (extService) Desc() *desc.Service {
+    return desc.NewService("extService").
+        AddContract(
+            desc.NewContract().
+                SetName("pay").
+                AddSelector(fasthttp.POST("/pay")).
+                SetInput(&extReq{}).
+                SetOutput(&extReq{}),
+        )
+}
+
+func TestExtensions(t *testing.T) {
+    integration := map[string]interface{}{"type": "http_proxy"}
+
+    sg := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WithExtension("x-logo", "logo.png").
+        WithTagExtension("extService", "owner", "payments").
+        WithOperationExtension("extService", "pay", "x-amazon-apigateway-integration", integration).
+        WithPropertyExtension("extReq", "amount", "x-currency", "USD")
+
+    doc := generateWith(t, sg, extService{})
+    if doc.Extensions["x-logo"] != "logo.png" {
+        t.Errorf("unexpected document extensions: %v", doc.Extensions)
+    }
+    if doc.Tags[0].Extensions["x-owner"] != "payments" {
+        t.Errorf("unexpected tag extensions: %v", doc.Tags[0].Extensions)
+    }
+    op := doc.Paths.Paths["/pay"].Post
+    if ex, ok := op.Extensions["x-amazon-apigateway-integration"].(map[string]interface{}); !ok || ex["type"] != "http_proxy" {
+        t.Errorf("unexpected operation extensions: %v", op.Extensions)
+    }
+
+    props := doc.Definitions["extReq"].Properties
+    if v := props["id"].Extensions["x-internal"]; v != true {
+        t.Errorf("unexpected id extensions: %v", props["id"].Extensions)
+    }
+    amount := props["amount"].Extensions
+    if amount["x-rate-limit"] != float64(100) || amount["x-currency"] != "USD" {
+        t.Errorf("unexpected amount extensions: %v", amount)
+    }
+    if v := props["note"].Extensions["x-Display-Name"]; v != "Note" {
+        t.Errorf("unexpected note extensions: %v", props["note"].Extensions)
+    }
+}
+
+func TestOperationExtensionContracts(t *testing.T) {
+    sg := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WithOperationExtension("anotherInvalidService", "raw", "owner", "another")
+
+    // Both services have a raw contract, but only the one of the given service is extended.
+    doc := generateWith(t, sg, invalidService{}, anotherInvalidService{})
+    if ext := doc.Paths.Paths["/raw"].Get.Extensions; ext["x-owner"] != nil {
+        t.Errorf("unexpected extensions of GET /raw: %v", ext)
+    }
+    if ext := doc.Paths.Paths["/another/raw"].Get.Extensions; ext["x-owner"] != "another" {
+        t.Errorf("unexpected extensions of GET /another/raw: %v", ext)
+    }
+
+    err := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WithOperationExtension("invalidService", "pay", "owner", "payments").
+        WriteTo(&strings.Builder{}, invalidService{})
+    if err == nil || !strings.Contains(err.Error(), "operation extensions of unknown contracts: invalidService.pay") {
+        t.Errorf("expected unknown contract error, got: %v", err)
+    }
+}
@@ -0,0 +1,175 @@
+package swagger
+
+import (
+    "fmt"
+    "io"
+    "path/filepath"
+    "strings"
+
+    "github.com/clubpay/ronykit/desc"
+    "github.com/go-openapi/spec"
+)
+
+// Route is a REST route of a contract, which is checked by the filters before it is added to
+// the document.
+type Route struct {
+    Service  string
+    Tags     []string
+    Contract string
+    Method   string
+    Path     string
+}
+
+// Filter reports if the route should be added to the document.
+type Filter func(r Route) bool
+
+// ServiceFilter only accepts the routes of the services with the given names.
+func ServiceFilter(names ...string) Filter {
+    return func(r Route) bool {
+        for _, n := range names {
+            if r.Service == n {
+                return true
+            }
+        }
+
+        return false
+    }
+}
+
+// TagFilter only accepts the routes which have at least one of the given tags. Every route is
+// tagged by its service name, and the tags which are set by WithServiceTags.
+func TagFilter(tags ...string) Filter {
+    return func(r Route) bool {
+        for _, t := range tags {
+            for _, rt := range r.Tags {
+                if rt == t {
+                    return true
+                }
+            }
+        }
+
+        return false
+    }
+}
+
+// PathPrefixFilter only accepts the routes whose path is under one of the given prefixes,
+// e.g. /v1 accepts /v1 and /v1/users but not /v10/users.
+func PathPrefixFilter(prefixes ...string) Filter {
+    return func(r Route) bool {
+        for _, p := range prefixes {
+            if hasPathPrefix(r.Path, p) {
+                return true
+            }
+        }
+
+        return false
+    }
+}
+
+// Not accepts the routes which are rejected by f.
+func Not(f Filter) Filter {
+    return func(r Route) bool {
+        return !f(r)
+    }
+}
+
+func hasPathPrefix(path, prefix string) bool {
+    prefix = strings.TrimSuffix(prefix, "/")
+
+    return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// Document is one of the documents which are generated by WriteDocumentsTo. Every document
+// has its own info block, and only includes the routes which pass its filters in addition to
+// the filters of the generator.
+type Document struct {
+    // Name identifies the document, WriteDocuments writes it to Name.json.
+    Name        string
+    Title       string
+    Version     string
+    Description string
+    // BasePath is set as the basePath of the document. If it is set, only the routes under
+    // it are added, and their paths are relative to it, e.g. /v1/users becomes /users.
+    BasePath string
+    Filters  []Filter
+}
+
+// WriteDocuments writes every document to a separate file in the dir.
+func (sg swaggerGen) WriteDocuments(dir string, docs []Document, services ...desc.ServiceDesc) error {
+    for _, doc := range docs {
+        err := writeToFile(
+            filepath.Join(dir, fmt.Sprintf("%s.json", doc.Name)),
+            func(w io.Writer) error { return sg.document(doc).WriteTo(w, services...) },
+        )
+        if err != nil {
+            return fmt.Errorf("document %s: %w", doc.Name, err)
+        }
+    }
+
+    return nil
+}
+
+// WriteDocumentsTo writes every document to the writer which is returned by newWriter.
+func (sg swaggerGen) WriteDocumentsTo(
+    newWriter func(doc Document) (io.Writer, error), docs []Document, services ...desc.ServiceDesc,
+) error {
+    for _, doc := range docs {
+        w, err := newWriter(doc)
+        if err == nil {
+            err = sg.document(doc).WriteTo(w, services...)
+        }
+        if err != nil {
+            return fmt.Errorf("document %s: %w", doc.Name, err)
+        }
+    }
+
+    return nil
+}
+
+// document returns a new generator with the same settings as sg, which generates doc.
+func (sg swaggerGen) document(doc Document) *swaggerGen {
+    g := NewSwagger(doc.Title, doc.Version, doc.Description)
+    g.tagName = sg.tagName
+    g.queryTag = sg.queryTag
+    g.pathTag = sg.pathTag
+    g.headerTag = sg.headerTag
+    g.collectionFormat = sg.collectionFormat
+    g.examples = sg.examples
+    g.serviceTags = sg.serviceTags
+    g.ext = sg.ext
+    g.warnings = sg.warnings
+    g.onWarning = sg.onWarning
+    g.filters = append(append([]Filter{}, sg.filters...), doc.Filters...)
+    if basePath := strings.TrimSuffix(doc.BasePath, "/"); basePath != "" {
+        g.s.BasePath = basePath
+        g.filters = append(g.filters, PathPrefixFilter(basePath))
+    }
+
+    return g
+}
+
+// accept reports if the route passes all the filters.
+func (sg swaggerGen) accept(r Route) bool {
+    for _, f := range sg.filters {
+        if !f(r) {
+            return false
+        }
+    }
+
+    return true
+}
+
+// docPath returns the path of the route in the document, which is relative to its base path.
+func docPath(doc *spec.Swagger, path string) string {
+    basePath := strings.TrimSuffix(doc.BasePath, "/")
+    if basePath == "" {
+        return path
+    }
+
+    path = strings.TrimPrefix(path, basePath)
+    if !strings.HasPrefix(path, "/") {
+        path = "/" + path
+    }
+
+    return path
+}
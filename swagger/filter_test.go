@@ -0,0 +1,138 @@
+package swagger_test
+
+import (
+    "io"
+    "strings"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/swagger"
+    "github.com/clubpay/ronykit/desc"
+    "github.com/clubpay/ronykit/std/gateway/fasthttp"
+    "github.com/go-openapi/spec"
+    "github.com/goccy/go-json"
+)
+
+type versionedService struct{}
+
+func (versionedService) Desc() *desc.Service {
+    return desc.NewService("userService").
+        AddContract(
+            desc.NewContract().
+                SetName("getUserV1").
+                AddSelector(fasthttp.GET("/v1/users/:id")).
+                SetInput(&nestedItem{}).
+                SetOutput(&nestedItem{}),
+        ).
+        AddContract(
+            desc.NewContract().
+                SetName("getUserV2").
+                AddSelector(fasthttp.GET("/v2/users/:id")).
+                SetInput(&nestedItem{}).
+                SetOutput(&nestedItem{}),
+        ).
+        AddContract(
+            desc.NewContract().
+                SetName("reindex").
+                AddSelector(fasthttp.POST("/internal/reindex")).
+                SetInput(&sampleReq{}).
+                SetOutput(&sampleRes{}),
+        )
+}
+
+// generate writes the document of the services, with the default options of the tests, and
+// parses it.
+func generate(t *testing.T, services ...desc.ServiceDesc) spec.Swagger {
+    t.Helper()
+
+    return generateWith(t, swagger.NewSwagger("TestTitle", "v0.0.1", "").WithTag("json"), services...)
+}
+
+// generateWith writes the document of the services by the generator sg, and parses it.
+func generateWith(t *testing.T, sg docWriter, services ...desc.ServiceDesc) spec.Swagger {
+    t.Helper()
+
+    sb := &strings.Builder{}
+    if err := sg.WriteTo(sb, services...); err != nil {
+        t.Fatal(err)
+    }
+
+    return parseDoc(t, sb.String())
+}
+
+// docWriter is the generator which NewSwagger returns.
+type docWriter interface {
+    WriteTo(w io.Writer, services ...desc.ServiceDesc) error
+}
+
+func parseDoc(t *testing.T, data string) spec.Swagger {
+    t.Helper()
+
+    doc := spec.Swagger{}
+    if err := json.Unmarshal([]byte(data), &doc); err != nil {
+        t.Fatal(err)
+    }
+
+    return doc
+}
+
+func TestFilter(t *testing.T) {
+    sg := swagger.NewSwagger("Public", "v1.0.0", "").
+        WithTag("json").
+        WithFilter(swagger.Not(swagger.PathPrefixFilter("/internal")))
+
+    doc := generateWith(t, sg, versionedService{}, listService{})
+    if _, ok := doc.Paths.Paths["/internal/reindex"]; ok {
+        t.Errorf("internal path should be filtered")
+    }
+    if _, ok := doc.Definitions["sampleReq"]; ok {
+        t.Errorf("definitions of filtered contracts should not be added")
+    }
+    if len(doc.Paths.Paths) != 3 || len(doc.Tags) != 2 {
+        t.Errorf("unexpected paths or tags: %v, %v", doc.Paths.Paths, doc.Tags)
+    }
+
+    sg = swagger.NewSwagger("Internal", "v1.0.0", "").
+        WithTag("json").
+        WithServiceTags("userService", "internal").
+        WithFilter(swagger.TagFilter("internal"), swagger.PathPrefixFilter("/internal"))
+
+    doc = generateWith(t, sg, versionedService{}, listService{})
+    if len(doc.Paths.Paths) != 1 || len(doc.Tags) != 1 || doc.Tags[0].Name != "userService" {
+        t.Errorf("unexpected paths or tags: %v, %v", doc.Paths.Paths, doc.Tags)
+    }
+}
+
+func TestDocuments(t *testing.T) {
+    out := map[string]*strings.Builder{}
+    err := swagger.NewSwagger("", "", "").
+        WithTag("json").
+        WithFilter(swagger.ServiceFilter("userService")).
+        WriteDocumentsTo(
+            func(doc swagger.Document) (io.Writer, error) {
+                out[doc.Name] = &strings.Builder{}
+
+                return out[doc.Name], nil
+            },
+            []swagger.Document{
+                {Name: "v1", Title: "Users V1", Version: "1.0.0", BasePath: "/v1"},
+                {Name: "v2", Title: "Users V2", Version: "2.0.0", BasePath: "/v2/"},
+            },
+            versionedService{}, listService{},
+        )
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    for name, title := range map[string]string{"v1": "Users V1", "v2": "Users V2"} {
+        doc := parseDoc(t, out[name].String())
+        if doc.Info.Title != title {
+            t.Errorf("unexpected title of %s: %s", name, doc.Info.Title)
+        }
+        if len(doc.Paths.Paths) != 1 {
+            t.Errorf("unexpected paths of %s: %v", name, doc.Paths.Paths)
+        }
+        if _, ok := doc.Paths.Paths["/users/{id}"]; !ok {
+            t.Errorf("expected relative path in %s: %v", name, doc.Paths.Paths)
+        }
+    }
+}
@@ -0,0 +1,1256 @@
+package swagger
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "go/format"
+    "io"
+    "net/http"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+    "text/template"
+    "unicode"
+
+    "github.com/go-openapi/spec"
+    "github.com/go-openapi/swag"
+)
+
+// goGen generates Go source code from a Swagger 2.0 or OpenAPI 3.x document. The generated
+// code contains the message structs tagged with tagName and swag tags, plus a desc.Service
+// skeleton for each tag of the document, using fasthttp.Selector for routing. Feeding the
+// generated services back to NewSwagger produces an equivalent document.
+type goGen struct {
+    pkgName string
+    tagName string
+}
+
+func NewGoGen(pkgName string) *goGen {
+    return &goGen{
+        pkgName: pkgName,
+        tagName: "json",
+    }
+}
+
+func (gg *goGen) WithTag(tagName string) *goGen {
+    gg.tagName = tagName
+
+    return gg
+}
+
+// WriteToFile reads the Swagger/OpenAPI document from specFile and writes the generated
+// Go code into filename.
+func (gg goGen) WriteToFile(filename string, specFile string) error {
+    data, err := os.ReadFile(specFile)
+    if err != nil {
+        return err
+    }
+
+    f, err := os.Create(filename)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    return gg.WriteTo(f, data)
+}
+
+// WriteTo generates the Go code from specData and writes it into w. specData could be either
+// in JSON or YAML format.
+func (gg goGen) WriteTo(w io.Writer, specData []byte) error {
+    doc, err := parseSpec(specData)
+    if err != nil {
+        return err
+    }
+
+    code, err := gg.generate(doc)
+    if err != nil {
+        return err
+    }
+
+    _, err = w.Write(code)
+
+    return err
+}
+
+func (gg goGen) generate(doc *spec.Swagger) ([]byte, error) {
+    d, err := newGoGenDoc(gg.pkgName, gg.tagName, doc)
+    if err != nil {
+        return nil, err
+    }
+
+    return renderGo(goGenTemplate, d)
+}
+
+func renderGo(t *template.Template, d *goGenDoc) ([]byte, error) {
+    buf := &bytes.Buffer{}
+    err := t.Execute(buf, d)
+    if err != nil {
+        return nil, err
+    }
+
+    return format.Source(buf.Bytes())
+}
+
+// newGoGenDoc prepares the document for the templates. It returns an error if the document has
+// references which the generator could not follow.
+func newGoGenDoc(pkgName, tagName string, doc *spec.Swagger) (*goGenDoc, error) {
+    d := &goGenDoc{
+        Pkg:     pkgName,
+        tagName: tagName,
+        defs:    doc.Definitions,
+        types:   map[string]*goGenType{},
+        idents:  map[string]struct{}{},
+    }
+    if doc.Info != nil {
+        d.Title = doc.Info.Title
+    }
+
+    defNames := make([]string, 0, len(doc.Definitions))
+    for name := range doc.Definitions {
+        defNames = append(defNames, name)
+    }
+    sort.Strings(defNames)
+    for _, name := range defNames {
+        d.newType(name)
+    }
+    for _, name := range defNames {
+        d.fillType(d.types[name], doc.Definitions[name])
+    }
+
+    tagDesc := map[string]string{}
+    for _, t := range doc.Tags {
+        tagDesc[t.Name] = t.Description
+    }
+
+    walkOperations(
+        doc,
+        func(method, path string, op *spec.Operation, commonParams []spec.Parameter) {
+            d.addOperation(method, path, op, commonParams, tagDesc)
+        },
+    )
+
+    d.breakCycles()
+
+    return d, d.err
+}
+
+// walkOperations calls f for every operation of the doc, sorted by path.
+func walkOperations(
+    doc *spec.Swagger, f func(method, path string, op *spec.Operation, commonParams []spec.Parameter),
+) {
+    if doc.Paths == nil {
+        return
+    }
+
+    paths := make([]string, 0, len(doc.Paths.Paths))
+    for p := range doc.Paths.Paths {
+        paths = append(paths, p)
+    }
+    sort.Strings(paths)
+
+    for _, p := range paths {
+        pathItem := doc.Paths.Paths[p]
+        for _, mo := range []struct {
+            method string
+            op     *spec.Operation
+        }{
+            {http.MethodGet, pathItem.Get},
+            {http.MethodPost, pathItem.Post},
+            {http.MethodPut, pathItem.Put},
+            {http.MethodPatch, pathItem.Patch},
+            {http.MethodDelete, pathItem.Delete},
+        } {
+            if mo.op == nil {
+                continue
+            }
+
+            f(mo.method, p, mo.op, pathItem.Parameters)
+        }
+    }
+}
+
+type goGenDoc struct {
+    Title    string
+    Pkg      string
+    Types    []*goGenType
+    Services []*goGenService
+
+    tagName string
+    defs    spec.Definitions
+    types   map[string]*goGenType
+    idents  map[string]struct{}
+    err     error
+}
+
+// fail keeps the first error of the document, so the generation could go on and the
+// templates are never rendered with a partial document.
+func (d *goGenDoc) fail(format string, args ...interface{}) {
+    if d.err == nil {
+        d.err = fmt.Errorf(format, args...)
+    }
+}
+
+type goGenType struct {
+    Name       string
+    Underlying string
+    Fields     []goGenField
+    IsErr      bool
+    CodeField  string
+    CodeExpr   string
+    ItemField  string
+}
+
+func (t *goGenType) hasField(jsonName string) bool {
+    for _, f := range t.Fields {
+        if f.jsonName == jsonName {
+            return true
+        }
+    }
+
+    return false
+}
+
+type goGenField struct {
+    Name string
+    Type string
+    Tag  string
+
+    jsonName string
+    enum     []string
+}
+
+type goGenService struct {
+    Name        string
+    TypeName    string
+    Description string
+    Contracts   []goGenContract
+}
+
+type goGenContract struct {
+    Name    string
+    Handler string
+    Method  string
+    Path    string
+    Input   string
+    Output  string
+    Errors  []goGenError
+
+    // OutputExpr creates a new output message, which is &T{} or new(T) if T is not a
+    // composite type.
+    OutputExpr string
+}
+
+type goGenError struct {
+    Type      string
+    CodeField string
+    Code      int
+    ItemField string
+    Item      string
+}
+
+// breakCycles makes the fields which cause a cycle of struct types to be pointers. The document
+// does not tell us which fields were pointers, but recursive types are only valid with pointers.
+func (d *goGenDoc) breakCycles() {
+    byName := make(map[string]*goGenType, len(d.Types))
+    for _, t := range d.Types {
+        byName[t.Name] = t
+    }
+
+    const (
+        visiting = 1
+        done     = 2
+    )
+    state := map[string]int{}
+
+    var visit func(t *goGenType)
+    visit = func(t *goGenType) {
+        state[t.Name] = visiting
+        for idx := range t.Fields {
+            ft, ok := byName[t.Fields[idx].Type]
+            if !ok {
+                continue
+            }
+
+            switch state[ft.Name] {
+            case visiting:
+                t.Fields[idx].Type = "*" + ft.Name
+            case done:
+            default:
+                visit(ft)
+            }
+        }
+        state[t.Name] = done
+    }
+
+    for _, t := range d.Types {
+        if state[t.Name] == 0 {
+            visit(t)
+        }
+    }
+}
+
+func (d *goGenDoc) uniqueIdent(name string) string {
+    ident := name
+    for i := 2; ; i++ {
+        if _, ok := d.idents[ident]; !ok {
+            break
+        }
+        ident = fmt.Sprintf("%s%d", name, i)
+    }
+    d.idents[ident] = struct{}{}
+
+    return ident
+}
+
+func (d *goGenDoc) addType(name string, s spec.Schema) *goGenType {
+    t := d.newType(name)
+    d.fillType(t, s)
+
+    return t
+}
+
+func (d *goGenDoc) newType(name string) *goGenType {
+    typeName := swag.ToGoName(name)
+    if isIdent(name) {
+        typeName = name
+    }
+
+    t := &goGenType{
+        Name: d.uniqueIdent(typeName),
+    }
+    d.types[name] = t
+    d.Types = append(d.Types, t)
+
+    return t
+}
+
+func (d *goGenDoc) fillType(t *goGenType, s spec.Schema) {
+    s = d.flatten(s, map[string]bool{})
+    propNames := make([]string, 0, len(s.Properties))
+    for pn := range s.Properties {
+        propNames = append(propNames, pn)
+    }
+    sort.Strings(propNames)
+
+    for _, pn := range propNames {
+        ps := s.Properties[pn]
+        optional := len(s.Required) > 0 && !swag.ContainsStrings(s.Required, pn)
+        d.addField(t, pn, d.goType(&ps, t.Name+swag.ToGoName(pn)), optional, ps.Enum, ps.Example)
+    }
+}
+
+func (d *goGenDoc) addField(
+    t *goGenType, name, goType string, optional bool, enum []interface{}, example interface{},
+) {
+    var (
+        swagTags   []string
+        enumValues []string
+    )
+    if optional {
+        swagTags = append(swagTags, "optional")
+    }
+    if len(enum) > 0 {
+        for _, v := range enum {
+            enumValues = append(enumValues, fmt.Sprint(v))
+        }
+        swagTags = append(swagTags, fmt.Sprintf("enum:%s", strings.Join(enumValues, swagValueSep)))
+    }
+    switch ex := example.(type) {
+    case nil, map[string]interface{}:
+    case []interface{}:
+        values := make([]string, 0, len(ex))
+        for _, v := range ex {
+            values = append(values, fmt.Sprint(v))
+        }
+        swagTags = append(swagTags, fmt.Sprintf("example:%s", strings.Join(values, swagValueSep)))
+    default:
+        swagTags = append(swagTags, fmt.Sprintf("example:%v", ex))
+    }
+
+    tag := fmt.Sprintf("%s:%q", d.tagName, name)
+    if len(swagTags) > 0 {
+        tag = fmt.Sprintf("%s %s:%q", tag, swagTagKey, strings.Join(swagTags, swagSep))
+    }
+
+    t.Fields = append(
+        t.Fields,
+        goGenField{
+            Name:     swag.ToGoName(name),
+            Type:     goType,
+            Tag:      tag,
+            jsonName: name,
+            enum:     enumValues,
+        },
+    )
+}
+
+// goType returns the Go type which represents the schema s. Inline object schemas are
+// generated as new types named by nameHint.
+func (d *goGenDoc) goType(s *spec.Schema, nameHint string) string {
+    if s == nil {
+        return "interface{}"
+    }
+    if ref := s.Ref.String(); ref != "" {
+        return d.refType(ref)
+    }
+    if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+        return d.goType(&s.AllOf[0], nameHint)
+    }
+    if len(s.AllOf) > 0 {
+        flat := d.flatten(*s, map[string]bool{})
+        s = &flat
+    }
+
+    switch {
+    case s.Type.Contains("array"):
+        if s.Items == nil || s.Items.Schema == nil {
+            return "[]interface{}"
+        }
+
+        return "[]" + d.goType(s.Items.Schema, nameHint+"Item")
+    case s.Type.Contains("string"):
+        if s.Format == "byte" || s.Format == "binary" {
+            return "[]byte"
+        }
+
+        return "string"
+    case s.Type.Contains("integer"):
+        switch s.Format {
+        case "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64":
+            return s.Format
+        }
+
+        return "int"
+    case s.Type.Contains("number"):
+        if s.Format == "float" {
+            return "float32"
+        }
+
+        return "float64"
+    case s.Type.Contains("boolean"):
+        return "bool"
+    case len(s.Properties) > 0:
+        if t, ok := d.types[nameHint]; ok {
+            return t.Name
+        }
+
+        return d.addType(nameHint, *s).Name
+    case s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+        return "map[string]" + d.goType(s.AdditionalProperties.Schema, nameHint+"Value")
+    case s.Type.Contains("object"):
+        return "map[string]interface{}"
+    }
+
+    return "interface{}"
+}
+
+// refType returns the type of the definition which ref refers to. Only the references to the
+// definitions of the document are supported.
+func (d *goGenDoc) refType(ref string) string {
+    name := strings.TrimPrefix(ref, definitionsPrefix)
+    if _, ok := d.defs[name]; !ok || name == ref {
+        d.fail("unsupported $ref %s", ref)
+
+        return "interface{}"
+    }
+
+    return d.types[name].Name
+}
+
+// flatten merges the allOf schemas of s, and the definitions they refer to, into the properties
+// of s. Go has no composition of types which could describe allOf as is. The properties of the
+// schemas which do not list the required ones are all required, as in fillType.
+func (d *goGenDoc) flatten(s spec.Schema, visited map[string]bool) spec.Schema {
+    if len(s.AllOf) == 0 {
+        return s
+    }
+
+    flat := s
+    flat.AllOf = nil
+    flat.Type = spec.StringOrArray{"object"}
+    flat.Properties = spec.SchemaProperties{}
+    flat.Required = nil
+    merge := func(part spec.Schema) {
+        for name, ps := range part.Properties {
+            if _, ok := flat.Properties[name]; ok {
+                continue
+            }
+            flat.Properties[name] = ps
+            if len(part.Required) == 0 || swag.ContainsStrings(part.Required, name) {
+                flat.Required = append(flat.Required, name)
+            }
+        }
+    }
+
+    own := s
+    own.AllOf = nil
+    merge(own)
+    for _, part := range s.AllOf {
+        if ref := part.Ref.String(); ref != "" {
+            name := strings.TrimPrefix(ref, definitionsPrefix)
+            def, ok := d.defs[name]
+            if !ok || name == ref || visited[name] {
+                d.fail("unsupported allOf $ref %s", ref)
+
+                continue
+            }
+            visited[name] = true
+            part = def
+        }
+        merge(d.flatten(part, visited))
+    }
+    sort.Strings(flat.Required)
+
+    return flat
+}
+
+// refName returns the name of the definition which s refers to.
+func refName(s *spec.Schema) string {
+    if s == nil {
+        return ""
+    }
+    ref := s.Ref.String()
+
+    return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// camelCase converts name to an exported Go identifier, e.g. get/some/:x/:y --> GetSomeXY
+func camelCase(name string) string {
+    sb := strings.Builder{}
+    words := strings.FieldsFunc(
+        name,
+        func(r rune) bool {
+            return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+        },
+    )
+    for _, w := range words {
+        sb.WriteString(strings.ToUpper(w[:1]))
+        sb.WriteString(w[1:])
+    }
+
+    return sb.String()
+}
+
+// isIdent reports if name could be used as a Go identifier as is.
+func isIdent(name string) bool {
+    for idx, r := range name {
+        switch {
+        case r == '_', unicode.IsLetter(r):
+        case idx > 0 && unicode.IsDigit(r):
+        default:
+            return false
+        }
+    }
+
+    return name != ""
+}
+
+func (d *goGenDoc) paramType(p spec.Parameter) string {
+    return d.goType(paramSchema(p), swag.ToGoName(p.Name))
+}
+
+func (d *goGenDoc) service(name string, tagDesc map[string]string) *goGenService {
+    for _, s := range d.Services {
+        if s.Name == name {
+            return s
+        }
+    }
+
+    typeName := swag.ToGoName(name)
+    if !strings.HasSuffix(strings.ToLower(typeName), "service") {
+        typeName += "Service"
+    }
+
+    s := &goGenService{
+        Name:        name,
+        TypeName:    d.uniqueIdent(typeName),
+        Description: tagDesc[name],
+    }
+    d.Services = append(d.Services, s)
+
+    return s
+}
+
+func (d *goGenDoc) addOperation(
+    method, path string, op *spec.Operation, commonParams []spec.Parameter, tagDesc map[string]string,
+) {
+    serviceName := "default"
+    if len(op.Tags) > 0 {
+        serviceName = op.Tags[0]
+    }
+    svc := d.service(serviceName, tagDesc)
+
+    handler := op.ID
+    if handler == "" {
+        handler = strings.ToLower(method) + path
+    }
+
+    params := append(append([]spec.Parameter{}, commonParams...), op.Parameters...)
+    c := goGenContract{
+        Name:    op.ID,
+        Handler: d.uniqueIdent(camelCase(handler)),
+        Method:  "Method" + swag.ToGoName(strings.ToLower(method)),
+        Path:    wildcardPath(ronyPath(path), params),
+    }
+
+    var input *goGenType
+    for _, p := range params {
+        if p.In != "body" {
+            continue
+        }
+        input = d.types[refName(p.Schema)]
+        if input == nil {
+            input = d.addType(c.Handler+"Request", derefSchema(p.Schema))
+        }
+    }
+    if input == nil {
+        input = d.typeWithParams(params)
+    }
+    if input == nil {
+        input = d.addType(c.Handler+"Request", spec.Schema{})
+    }
+    for _, p := range params {
+        if p.In != "path" && p.In != "query" {
+            continue
+        }
+        if input.hasField(p.Name) {
+            continue
+        }
+        d.addField(input, p.Name, d.paramType(p), !p.Required, p.Enum, p.Extensions[exampleExtension])
+    }
+    c.Input = input.Name
+
+    c.Output = "ronykit.RawMessage"
+    if op.Responses != nil {
+        codes := make([]int, 0, len(op.Responses.StatusCodeResponses))
+        for code := range op.Responses.StatusCodeResponses {
+            codes = append(codes, code)
+        }
+        sort.Ints(codes)
+
+        for _, code := range codes {
+            res := op.Responses.StatusCodeResponses[code]
+            if res.Schema == nil {
+                continue
+            }
+
+            switch {
+            case code >= 200 && code < 300:
+                if c.Output == "ronykit.RawMessage" {
+                    c.Output = d.outputType(res.Schema, c.Handler+"Response")
+                }
+            case code >= 400:
+                items := []string{""}
+                if strings.HasPrefix(res.Description, "Items: ") {
+                    items = strings.Split(strings.TrimPrefix(res.Description, "Items: "), ", ")
+                }
+                c.Errors = append(c.Errors, d.errors(code, oneOfSchemas(res.Schema), items)...)
+            }
+        }
+    }
+
+    c.OutputExpr = fmt.Sprintf("&%s{}", c.Output)
+    if t, ok := d.types[c.Output]; ok && t.Underlying != "" && !isComposite(t.Underlying) {
+        c.OutputExpr = fmt.Sprintf("new(%s)", c.Output)
+    }
+
+    svc.Contracts = append(svc.Contracts, c)
+}
+
+// outputType returns the type of the output message of the response schema s. Since messages
+// are pointers to named types, the schemas which are not objects are wrapped in a new type,
+// e.g. type GetNameResponse string, and the schemas without a type are raw messages.
+func (d *goGenDoc) outputType(s *spec.Schema, name string) string {
+    goType := d.goType(s, name)
+    for _, t := range d.Types {
+        if t.Name == goType {
+            return goType
+        }
+    }
+    if goType == "interface{}" {
+        return "ronykit.RawMessage"
+    }
+
+    t := &goGenType{
+        Name:       d.uniqueIdent(swag.ToGoName(name)),
+        Underlying: goType,
+    }
+    d.types[t.Name] = t
+    d.Types = append(d.Types, t)
+
+    return t.Name
+}
+
+// isComposite reports if the Go type could be created by a composite literal.
+func isComposite(goType string) bool {
+    return strings.HasPrefix(goType, "[") || strings.HasPrefix(goType, "map[")
+}
+
+// errors returns the possible errors of the status code. If there are more than one error types,
+// each item is assigned to the type which lists it in the enum of its item field, or to the
+// first type otherwise.
+func (d *goGenDoc) errors(code int, schemas []spec.Schema, items []string) []goGenError {
+    var (
+        errs     []goGenError
+        errTypes []*goGenType
+    )
+    for idx := range schemas {
+        errType := d.types[refName(&schemas[idx])]
+        if errType == nil || !errType.asError() {
+            continue
+        }
+        errTypes = append(errTypes, errType)
+    }
+    if len(errTypes) == 0 {
+        return nil
+    }
+
+    for _, item := range items {
+        errType := errTypes[0]
+        for _, t := range errTypes {
+            if t.hasItem(item) {
+                errType = t
+
+                break
+            }
+        }
+
+        errs = append(
+            errs,
+            goGenError{
+                Type:      errType.Name,
+                CodeField: errType.CodeField,
+                Code:      code,
+                ItemField: errType.ItemField,
+                Item:      item,
+            },
+        )
+    }
+
+    return errs
+}
+
+// typeWithParams returns the type which has exactly the same fields as the path and query
+// params. This is how NewSwagger describes the input of the operations without body.
+func (d *goGenDoc) typeWithParams(params []spec.Parameter) *goGenType {
+    var names []string
+    for _, p := range params {
+        if p.In == "path" || p.In == "query" {
+            names = append(names, p.Name)
+        }
+    }
+    if len(names) == 0 {
+        return nil
+    }
+
+    for _, t := range d.Types {
+        if len(t.Fields) != len(names) {
+            continue
+        }
+
+        found := true
+        for _, n := range names {
+            found = found && t.hasField(n)
+        }
+        if found {
+            return t
+        }
+    }
+
+    return nil
+}
+
+// hasItem reports if item is listed in the enum of the item field of the error type.
+func (t *goGenType) hasItem(item string) bool {
+    for _, f := range t.Fields {
+        if f.Name == t.ItemField {
+            return swag.ContainsStrings(f.enum, item)
+        }
+    }
+
+    return false
+}
+
+// asError marks the type as an error message if it has an integer code field.
+func (t *goGenType) asError() bool {
+    if t.IsErr {
+        return true
+    }
+
+    for _, f := range t.Fields {
+        switch {
+        case t.CodeField == "" && strings.EqualFold(f.jsonName, "code") && strings.Contains(f.Type, "int"):
+            t.CodeField = f.Name
+            t.CodeExpr = fmt.Sprintf("e.%s", f.Name)
+            if f.Type != "int" {
+                t.CodeExpr = fmt.Sprintf("int(e.%s)", f.Name)
+            }
+        case t.ItemField == "" && strings.EqualFold(f.jsonName, "item") && f.Type == "string":
+            t.ItemField = f.Name
+        }
+    }
+    t.IsErr = t.CodeField != ""
+
+    return t.IsErr
+}
+
+func derefSchema(s *spec.Schema) spec.Schema {
+    if s == nil {
+        return spec.Schema{}
+    }
+
+    return *s
+}
+
+// ronyPath converts the swagger url format to ronykit mux format urls.
+// e.g. /some/path/{x1} --> /some/path/:x1
+func ronyPath(path string) string {
+    sb := strings.Builder{}
+    for idx, p := range strings.Split(path, "/") {
+        if idx > 0 {
+            sb.WriteRune('/')
+        }
+        if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+            sb.WriteRune(':')
+            sb.WriteString(p[1 : len(p)-1])
+        } else {
+            sb.WriteString(p)
+        }
+    }
+
+    return sb.String()
+}
+
+// wildcardPath converts the path params of the ronykit mux format path which are marked as
+// wildcards by NewSwagger, e.g. /files/:filepath --> /files/*filepath
+func wildcardPath(path string, params []spec.Parameter) string {
+    segs := strings.Split(path, "/")
+    for _, p := range params {
+        if wildcard, _ := p.Extensions.GetBool(wildcardExtension); !wildcard || p.In != "path" {
+            continue
+        }
+        for idx, seg := range segs {
+            if seg == ":"+p.Name {
+                segs[idx] = "*" + p.Name
+            }
+        }
+    }
+
+    return strings.Join(segs, "/")
+}
+
+// parseSpec parses a Swagger 2.0 or OpenAPI 3.x document. OpenAPI 3.x documents are
+// converted to their Swagger 2.0 equivalent.
+func parseSpec(data []byte) (*spec.Swagger, error) {
+    if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+        yamlDoc, err := swag.BytesToYAMLDoc(data)
+        if err != nil {
+            return nil, err
+        }
+
+        data, err = swag.YAMLToJSON(yamlDoc)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    version := struct {
+        Swagger string `json:"swagger"`
+        OpenAPI string `json:"openapi"`
+    }{}
+    err := json.Unmarshal(data, &version)
+    if err != nil {
+        return nil, err
+    }
+
+    switch {
+    case strings.HasPrefix(version.Swagger, "2."):
+        doc := &spec.Swagger{}
+        err = json.Unmarshal(data, doc)
+        if err != nil {
+            return nil, err
+        }
+
+        return doc, resolveRefs(doc)
+    case strings.HasPrefix(version.OpenAPI, "3."):
+        // OpenAPI 3.x schemas are compatible with Swagger 2.0 ones for our purpose,
+        // we only need to fix the references.
+        data = bytes.ReplaceAll(data, []byte(`"#/components/schemas/`), []byte(`"#/definitions/`))
+        doc := &oas3Doc{}
+        err = json.Unmarshal(data, doc)
+        if err != nil {
+            return nil, err
+        }
+
+        return doc.toSwagger()
+    }
+
+    return nil, fmt.Errorf("unsupported spec version: %q", version.Swagger+version.OpenAPI)
+}
+
+const (
+    parametersPrefix = "#/parameters/"
+    responsesPrefix  = "#/responses/"
+)
+
+// resolveRefs replaces the references to the shared params and responses of the Swagger 2.0
+// document with their definitions, so the operations could be generated as if they were inline.
+func resolveRefs(doc *spec.Swagger) error {
+    if doc.Paths == nil {
+        return nil
+    }
+
+    for path, pathItem := range doc.Paths.Paths {
+        params, err := resolveParams(doc, pathItem.Parameters)
+        if err != nil {
+            return fmt.Errorf("%s: %w", path, err)
+        }
+        pathItem.Parameters = params
+
+        for method, op := range pathOperations(pathItem) {
+            if op == nil {
+                continue
+            }
+
+            op.Parameters, err = resolveParams(doc, op.Parameters)
+            if err != nil {
+                return fmt.Errorf("%s %s: %w", method, path, err)
+            }
+            if op.Responses == nil {
+                continue
+            }
+            for code, res := range op.Responses.StatusCodeResponses {
+                ref := res.Ref.String()
+                if ref == "" {
+                    continue
+                }
+                shared, ok := doc.Responses[strings.TrimPrefix(ref, responsesPrefix)]
+                if !ok || !strings.HasPrefix(ref, responsesPrefix) {
+                    return fmt.Errorf("%s %s: unsupported $ref %s", method, path, ref)
+                }
+                op.Responses.StatusCodeResponses[code] = shared
+            }
+        }
+        doc.Paths.Paths[path] = pathItem
+    }
+
+    return nil
+}
+
+func resolveParams(doc *spec.Swagger, params []spec.Parameter) ([]spec.Parameter, error) {
+    for idx, p := range params {
+        ref := p.Ref.String()
+        if ref == "" {
+            continue
+        }
+        shared, ok := doc.Parameters[strings.TrimPrefix(ref, parametersPrefix)]
+        if !ok || !strings.HasPrefix(ref, parametersPrefix) {
+            return nil, fmt.Errorf("unsupported $ref %s", ref)
+        }
+        params[idx] = shared
+    }
+
+    return params, nil
+}
+
+type oas3Doc struct {
+    Info       *spec.Info              `json:"info"`
+    Tags       []spec.Tag              `json:"tags"`
+    Paths      map[string]oas3PathItem `json:"paths"`
+    Components struct {
+        Schemas       map[string]spec.Schema   `json:"schemas"`
+        Parameters    map[string]oas3Parameter `json:"parameters"`
+        RequestBodies map[string]oas3Body      `json:"requestBodies"`
+        Responses     map[string]oas3Response  `json:"responses"`
+    } `json:"components"`
+}
+
+type oas3PathItem struct {
+    Get        *oas3Operation  `json:"get"`
+    Put        *oas3Operation  `json:"put"`
+    Post       *oas3Operation  `json:"post"`
+    Delete     *oas3Operation  `json:"delete"`
+    Patch      *oas3Operation  `json:"patch"`
+    Parameters []oas3Parameter `json:"parameters"`
+}
+
+type oas3Operation struct {
+    OperationID string                  `json:"operationId"`
+    Description string                  `json:"description"`
+    Tags        []string                `json:"tags"`
+    Parameters  []oas3Parameter         `json:"parameters"`
+    RequestBody *oas3Body               `json:"requestBody"`
+    Responses   map[string]oas3Response `json:"responses"`
+}
+
+type oas3Parameter struct {
+    Ref         string       `json:"$ref"`
+    Name        string       `json:"name"`
+    In          string       `json:"in"`
+    Description string       `json:"description"`
+    Required    bool         `json:"required"`
+    Schema      *spec.Schema `json:"schema"`
+}
+
+type oas3Body struct {
+    Ref      string                   `json:"$ref"`
+    Required bool                     `json:"required"`
+    Content  map[string]oas3MediaType `json:"content"`
+}
+
+type oas3MediaType struct {
+    Schema *spec.Schema `json:"schema"`
+}
+
+type oas3Response struct {
+    Ref         string                   `json:"$ref"`
+    Description string                   `json:"description"`
+    Content     map[string]oas3MediaType `json:"content"`
+}
+
+// toSwagger converts the document to Swagger 2.0. The references to the components other than
+// the schemas are replaced with the components, and it returns an error if they do not exist.
+func (doc oas3Doc) toSwagger() (*spec.Swagger, error) {
+    out := &spec.Swagger{}
+    out.Swagger = "2.0"
+    out.Info = doc.Info
+    out.Tags = doc.Tags
+    out.Definitions = doc.Components.Schemas
+    out.Paths = &spec.Paths{Paths: map[string]spec.PathItem{}}
+
+    for p, item := range doc.Paths {
+        pathItem := spec.PathItem{}
+        for _, o := range []struct {
+            op  *oas3Operation
+            dst **spec.Operation
+        }{
+            {item.Get, &pathItem.Get},
+            {item.Put, &pathItem.Put},
+            {item.Post, &pathItem.Post},
+            {item.Delete, &pathItem.Delete},
+            {item.Patch, &pathItem.Patch},
+        } {
+            op, err := doc.operation(o.op)
+            if err != nil {
+                return nil, fmt.Errorf("%s: %w", p, err)
+            }
+            *o.dst = op
+        }
+
+        params, err := doc.parameters(item.Parameters)
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", p, err)
+        }
+        pathItem.Parameters = params
+        out.Paths.Paths[p] = pathItem
+    }
+
+    return out, nil
+}
+
+func (doc oas3Doc) operation(op *oas3Operation) (*spec.Operation, error) {
+    if op == nil {
+        return nil, nil
+    }
+
+    swagOp := spec.NewOperation(op.OperationID).
+        WithTags(op.Tags...).
+        WithDescription(op.Description)
+    params, err := doc.parameters(op.Parameters)
+    if err != nil {
+        return nil, err
+    }
+    swagOp.Parameters = params
+    if body := op.RequestBody; body != nil {
+        if body.Ref != "" {
+            name, ok := oas3ComponentName(body.Ref, "requestBodies")
+            shared, found := doc.Components.RequestBodies[name]
+            if !ok || !found {
+                return nil, fmt.Errorf("unsupported $ref %s", body.Ref)
+            }
+            body = &shared
+        }
+        if s := oas3Schema(body.Content); s != nil {
+            swagOp.AddParam(spec.BodyParam("body", s))
+        }
+    }
+
+    for code, res := range op.Responses {
+        statusCode, err := strconv.Atoi(code)
+        if err != nil {
+            continue
+        }
+        if res.Ref != "" {
+            name, ok := oas3ComponentName(res.Ref, "responses")
+            shared, found := doc.Components.Responses[name]
+            if !ok || !found {
+                return nil, fmt.Errorf("unsupported $ref %s", res.Ref)
+            }
+            res = shared
+        }
+
+        swagOp.RespondsWith(
+            statusCode,
+            spec.NewResponse().
+                WithSchema(oas3Schema(res.Content)).
+                WithDescription(res.Description),
+        )
+    }
+
+    return swagOp, nil
+}
+
+func (doc oas3Doc) parameters(params []oas3Parameter) ([]spec.Parameter, error) {
+    out := make([]spec.Parameter, 0, len(params))
+    for _, p := range params {
+        if p.Ref != "" {
+            name, ok := oas3ComponentName(p.Ref, "parameters")
+            shared, found := doc.Components.Parameters[name]
+            if !ok || !found {
+                return nil, fmt.Errorf("unsupported $ref %s", p.Ref)
+            }
+            p = shared
+        }
+
+        sp := spec.Parameter{
+            ParamProps: spec.ParamProps{
+                Name:        p.Name,
+                In:          p.In,
+                Description: p.Description,
+                Required:    p.Required,
+                Schema:      p.Schema,
+            },
+        }
+        if p.Schema != nil {
+            sp.Enum = p.Schema.Enum
+        }
+        out = append(out, sp)
+    }
+
+    return out, nil
+}
+
+// oas3ComponentName returns the name of the component of the kind, e.g. parameters, which ref
+// refers to. It reports false if ref does not refer to a component of the kind.
+func oas3ComponentName(ref, kind string) (string, bool) {
+    prefix := fmt.Sprintf("#/components/%s/", kind)
+
+    return strings.TrimPrefix(ref, prefix), strings.HasPrefix(ref, prefix)
+}
+
+// oas3Schema returns the schema of the JSON content if exists, otherwise the
+// schema of the first content type.
+func oas3Schema(content map[string]oas3MediaType) *spec.Schema {
+    if mt, ok := content["application/json"]; ok {
+        return mt.Schema
+    }
+
+    contentTypes := make([]string, 0, len(content))
+    for ct := range content {
+        contentTypes = append(contentTypes, ct)
+    }
+    sort.Strings(contentTypes)
+    for _, ct := range contentTypes {
+        return content[ct].Schema
+    }
+
+    return nil
+}
+
+// goTypesTemplate renders the goGenDoc.Types, the error types implement ronykit.ErrorMessage.
+const goTypesTemplate = `
+{{ range .Types }}
+{{- if .Underlying }}
+type {{ .Name }} {{ .Underlying }}
+{{ else }}
+type {{ .Name }} struct {
+{{- range .Fields }}
+    {{ .Name }} {{ .Type }} ` + "`{{ .Tag }}`" + `
+{{- end }}
+}
+{{ if .IsErr }}
+var _ ronykit.ErrorMessage = (*{{ .Name }})(nil)
+
+func (e {{ .Name }}) GetCode() int {
+    return {{ .CodeExpr }}
+}
+
+func (e {{ .Name }}) GetItem() string {
+    return {{ if .ItemField }}e.{{ .ItemField }}{{ else }}""{{ end }}
+}
+
+func (e {{ .Name }}) Error() string {
+    return fmt.Sprintf("%d: %s", e.GetCode(), e.GetItem())
+}
+{{ end }}
+{{- end }}
+{{ end }}
+`
+
+var goGenTemplate = template.Must(template.New("goGen").Parse(`
+{{- if .Title }}// Code generated from "{{ .Title }}" by swagger.GoGen.{{ end }}
+
+package {{ .Pkg }}
+
+import (
+    {{- if .HasErrors }}
+    "fmt"
+    {{ end }}
+    "github.com/clubpay/ronykit"
+    "github.com/clubpay/ronykit/desc"
+    "github.com/clubpay/ronykit/std/gateway/fasthttp"
+)
+
+` + goTypesTemplate + `
+{{ range $svc := .Services }}
+type {{ $svc.TypeName }} struct{}
+
+var _ desc.ServiceDesc = {{ $svc.TypeName }}{}
+
+func (svc {{ $svc.TypeName }}) Desc() *desc.Service {
+    return desc.NewService({{ printf "%q" $svc.Name }}).
+        {{- if $svc.Description }}
+        SetDescription({{ printf "%q" $svc.Description }}).
+        {{- end }}
+        AddContract(
+        {{- range $svc.Contracts }}
+            desc.NewContract().
+                {{- if .Name }}
+                SetName({{ printf "%q" .Name }}).
+                {{- end }}
+                AddSelector(fasthttp.Selector{
+                    Method: fasthttp.{{ .Method }},
+                    Path:   {{ printf "%q" .Path }},
+                }).
+                SetInput(&{{ .Input }}{}).
+                SetOutput({{ .OutputExpr }}).
+                {{- range .Errors }}
+                AddError(&{{ .Type }}{ {{- .CodeField }}: {{ .Code }}{{ if .ItemField }}, {{ .ItemField }}: {{ printf "%q" .Item }}{{ end }}}).
+                {{- end }}
+                SetHandler(svc.{{ .Handler }}),
+        {{- end }}
+        )
+}
+{{ range $svc.Contracts }}
+func (svc {{ $svc.TypeName }}) {{ .Handler }}(ctx *ronykit.Context) {
+    ctx.Out().
+        SetMsg({{ .OutputExpr }}).
+        Send()
+}
+{{ end }}
+{{ end }}
+`))
+
+// HasErrors reports if any of the generated types is an error message.
+func (d *goGenDoc) HasErrors() bool {
+    for _, t := range d.Types {
+        if t.IsErr {
+            return true
+        }
+    }
+
+    return false
+}
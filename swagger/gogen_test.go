@@ -0,0 +1,441 @@
+package swagger_test
+
+import (
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/swagger"
+)
+
+func TestGoGen(t *testing.T) {
+    swagJSON := &strings.Builder{}
+    err := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WriteTo(swagJSON, testService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    code := &strings.Builder{}
+    err = swagger.NewGoGen("api").WriteTo(code, []byte(swagJSON.String()))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    vetGo(t, code.String())
+
+    for _, expected := range []string{
+        "type sampleReq struct",
+        `X string   ` + "`json:\"x\"`",
+        "type TestService struct{}",
+        `desc.NewService("testService")`,
+        `Path:   "/some/:x/:y"`,
+        "SetInput(&sampleReq{})",
+        "SetOutput(&anotherRes{})",
+    } {
+        if !strings.Contains(code.String(), expected) {
+            t.Errorf("expected %q in generated code:\n%s", expected, code.String())
+        }
+    }
+}
+
+const sampleOAS3 = `
+openapi: 3.0.1
+info:
+  title: Pets
+  version: v1
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      tags: [pets]
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: integer
+            format: int64
+        - name: fields
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        "404":
+          description: "Items: PET"
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Error'
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+          format: int64
+        kind:
+          type: string
+          enum: [cat, dog]
+    Error:
+      type: object
+      properties:
+        code:
+          type: integer
+        item:
+          type: string
+`
+
+func TestGoGenOpenAPI3(t *testing.T) {
+    code := &strings.Builder{}
+    err := swagger.NewGoGen("pets").WriteTo(code, []byte(sampleOAS3))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    vetGo(t, code.String())
+
+    for _, expected := range []string{
+        "type PetsService struct{}",
+        "type GetPetRequest struct",
+        "PetID  int64    `json:\"petId\"`",
+        "`json:\"fields\" swag:\"optional\"`",
+        "`json:\"kind\" swag:\"optional;enum:cat,dog\"`",
+        `Path:   "/pets/:petId"`,
+        `AddError(&Error{Code: 404, Item: "PET"})`,
+    } {
+        if !strings.Contains(code.String(), expected) {
+            t.Errorf("expected %q in generated code:\n%s", expected, code.String())
+        }
+    }
+}
+
+const componentsOAS3 = `
+openapi: 3.0.1
+info:
+  title: Orders
+  version: v1
+paths:
+  /orders/{orderId}:
+    get:
+      operationId: getOrder
+      tags: [orders]
+      parameters:
+        - $ref: '#/components/parameters/OrderID'
+      responses:
+        "200":
+          $ref: '#/components/responses/Order'
+  /orders:
+    post:
+      operationId: createOrder
+      tags: [orders]
+      requestBody:
+        $ref: '#/components/requestBodies/NewOrder'
+      responses:
+        "200":
+          $ref: '#/components/responses/Order'
+components:
+  parameters:
+    OrderID:
+      name: orderId
+      in: path
+      required: true
+      schema:
+        type: string
+  requestBodies:
+    NewOrder:
+      required: true
+      content:
+        application/json:
+          schema:
+            type: object
+            properties:
+              item:
+                type: string
+              count:
+                type: integer
+  responses:
+    Order:
+      description: ok
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Order'
+  schemas:
+    Base:
+      type: object
+      properties:
+        id:
+          type: string
+    Order:
+      allOf:
+        - $ref: '#/components/schemas/Base'
+        - type: object
+          properties:
+            item:
+              type: string
+`
+
+func TestGoGenComponents(t *testing.T) {
+    code := &strings.Builder{}
+    err := swagger.NewGoGen("main").WriteTo(code, []byte(componentsOAS3))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    for _, expected := range []string{
+        "OrderID string `json:\"orderId\"`",
+        "Count int    `json:\"count\"`",
+        "type Order struct {\n\tID   string `json:\"id\"`\n\tItem string `json:\"item\"`",
+        "SetOutput(&Order{})",
+    } {
+        if !strings.Contains(code.String(), expected) {
+            t.Errorf("expected %q in generated code:\n%s", expected, code.String())
+        }
+    }
+
+    // The generated services describe the same operations.
+    doc := parseDoc(t, runGo(t, code.String(), "OrdersService{}"))
+    get := doc.Paths.Paths["/orders/{orderId}"].Get
+    if get == nil || len(get.Parameters) != 1 || get.Parameters[0].In != "path" {
+        t.Errorf("unexpected GET /orders/{orderId}: %v", get)
+    }
+    post := doc.Paths.Paths["/orders"].Post
+    if post == nil || post.Responses.StatusCodeResponses[200].Schema.Ref.String() != "#/definitions/Order" {
+        t.Errorf("unexpected POST /orders: %v", post)
+    }
+}
+
+func TestGoGenSwaggerRefs(t *testing.T) {
+    const swaggerRefs = `{
+        "swagger": "2.0",
+        "info": {"title": "Files", "version": "v1"},
+        "parameters": {
+            "bucket": {"name": "bucket", "in": "path", "required": true, "type": "string"}
+        },
+        "paths": {
+            "/files/{bucket}": {
+                "get": {
+                    "operationId": "listFiles",
+                    "parameters": [{"$ref": "#/parameters/bucket"}],
+                    "responses": {"200": {"$ref": "#/responses/files"}}
+                }
+            }
+        },
+        "responses": {
+            "files": {"description": "ok", "schema": {"type": "array", "items": {"type": "string"}}}
+        }
+    }`
+
+    code := &strings.Builder{}
+    err := swagger.NewGoGen("files").WriteTo(code, []byte(swaggerRefs))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    vetGo(t, code.String())
+
+    for _, expected := range []string{
+        "Bucket string `json:\"bucket\"`",
+        "type ListFilesResponse []string",
+    } {
+        if !strings.Contains(code.String(), expected) {
+            t.Errorf("expected %q in generated code:\n%s", expected, code.String())
+        }
+    }
+
+    err = swagger.NewGoGen("files").WriteTo(
+        &strings.Builder{}, []byte(strings.ReplaceAll(swaggerRefs, "#/parameters/bucket", "#/parameters/missing")),
+    )
+    if err == nil || !strings.Contains(err.Error(), "unsupported $ref #/parameters/missing") {
+        t.Errorf("expected unsupported $ref error, got: %v", err)
+    }
+}
+
+func TestGoGenWildcard(t *testing.T) {
+    swagJSON := &strings.Builder{}
+    err := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WriteTo(swagJSON, pathService{path: "/files/:bucket/*filepath"})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    code := &strings.Builder{}
+    err = swagger.NewGoGen("api").WriteTo(code, []byte(swagJSON.String()))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !strings.Contains(code.String(), `Path:   "/files/:bucket/*filepath"`) {
+        t.Errorf("expected the wildcard route in generated code:\n%s", code.String())
+    }
+}
+
+const scalarOAS3 = `
+openapi: 3.0.1
+info:
+  title: Names
+  version: v1
+paths:
+  /names/{id}:
+    get:
+      operationId: getName
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: string
+  /names:
+    get:
+      operationId: listNames
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: string
+  /count:
+    get:
+      operationId: count
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: integer
+                format: int64
+  /any:
+    get:
+      operationId: getAny
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {}
+`
+
+func TestGoGenScalarResponses(t *testing.T) {
+    code := &strings.Builder{}
+    err := swagger.NewGoGen("names").WriteTo(code, []byte(scalarOAS3))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    vetGo(t, code.String())
+
+    for _, expected := range []string{
+        "type GetNameResponse string",
+        "type ListNamesResponse []string",
+        "type CountResponse int64",
+        "SetOutput(new(GetNameResponse))",
+        "SetOutput(&ListNamesResponse{})",
+        "SetOutput(&ronykit.RawMessage{})",
+    } {
+        if !strings.Contains(code.String(), expected) {
+            t.Errorf("expected %q in generated code:\n%s", expected, code.String())
+        }
+    }
+}
+
+// runGo runs the generated code of the main package, which writes the document of the services
+// as generated by NewSwagger.
+func runGo(t *testing.T, code string, services ...string) string {
+    t.Helper()
+
+    goBin, err := exec.LookPath("go")
+    if err != nil {
+        t.Skip("go command is not found")
+    }
+
+    dir, err := os.MkdirTemp(".", "_gen")
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+    mainCode := fmt.Sprintf(`package main
+
+import (
+    "os"
+
+    "github.com/clubpay/ronycontrib/swagger"
+)
+
+func main() {
+    err := swagger.NewSwagger("RoundTrip", "v1", "").WithTag("json").WriteTo(os.Stdout, %s)
+    if err != nil {
+        panic(err)
+    }
+}
+`, strings.Join(services, ", "))
+    for name, src := range map[string]string{"gen.go": code, "main.go": mainCode} {
+        err = os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600)
+        if err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    cmd := exec.Command(goBin, "run", "./"+filepath.Base(dir))
+    stderr := &strings.Builder{}
+    cmd.Stderr = stderr
+    out, err := cmd.Output()
+    if err != nil {
+        t.Fatalf("generated code does not run: %v\n%s\n%s", err, stderr, code)
+    }
+
+    return string(out)
+}
+
+// vetGo type-checks the generated code by running go vet on it, in a package of this module
+// which is ignored by the ./... patterns.
+func vetGo(t *testing.T, code string) {
+    t.Helper()
+
+    goBin, err := exec.LookPath("go")
+    if err != nil {
+        t.Skip("go command is not found")
+    }
+
+    dir, err := os.MkdirTemp(".", "_gen")
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+    err = os.WriteFile(filepath.Join(dir, "gen.go"), []byte(code), 0o600)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    out, err := exec.Command(goBin, "vet", "./"+filepath.Base(dir)).CombinedOutput()
+    if err != nil {
+        t.Fatalf("generated code does not compile: %v\n%s\n%s", err, out, code)
+    }
+}
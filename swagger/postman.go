@@ -0,0 +1,327 @@
+package swagger
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "sort"
+    "strings"
+
+    "github.com/clubpay/ronykit/desc"
+    "github.com/go-openapi/spec"
+)
+
+const (
+    postmanSchema      = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+    postmanBaseURLVar  = "baseUrl"
+    postmanAuthVar     = "authToken"
+    postmanDefaultHost = "http://localhost"
+)
+
+// postmanGen exports the REST contracts of the services as a Postman Collection v2.1.
+// Every service becomes a folder, and every REST selector becomes a request with an
+// example body built from the input schema.
+type postmanGen struct {
+    name    string
+    tagName string
+    baseURL string
+}
+
+func NewPostman(name string) *postmanGen {
+    return &postmanGen{
+        name:    name,
+        tagName: "json",
+        baseURL: postmanDefaultHost,
+    }
+}
+
+func (pg *postmanGen) WithTag(tagName string) *postmanGen {
+    pg.tagName = tagName
+
+    return pg
+}
+
+// WithBaseURL sets the initial value of the baseUrl variable of the collection.
+func (pg *postmanGen) WithBaseURL(baseURL string) *postmanGen {
+    pg.baseURL = baseURL
+
+    return pg
+}
+
+func (pg postmanGen) WriteToFile(filename string, services ...desc.ServiceDesc) error {
+    return writeToFile(filename, func(w io.Writer) error { return pg.WriteTo(w, services...) })
+}
+
+func (pg postmanGen) WriteTo(w io.Writer, services ...desc.ServiceDesc) error {
+    sg := NewSwagger(pg.name, internalDocVersion, "").WithTag(pg.tagName)
+    if err := sg.build(services...); err != nil {
+        return err
+    }
+
+    collectionJSON, err := json.Marshal(pg.collection(sg.s))
+    if err != nil {
+        return err
+    }
+
+    _, err = w.Write(collectionJSON)
+
+    return err
+}
+
+func (pg postmanGen) collection(doc *spec.Swagger) *postmanCollection {
+    c := &postmanCollection{
+        Info: postmanInfo{
+            Name:   pg.name,
+            Schema: postmanSchema,
+        },
+        Auth: &postmanAuth{
+            Type: "bearer",
+            Bearer: []postmanKV{
+                {Key: "token", Value: fmt.Sprintf("{{%s}}", postmanAuthVar), Type: "string"},
+            },
+        },
+        Variable: []postmanKV{
+            {Key: postmanBaseURLVar, Value: pg.baseURL, Type: "string"},
+            {Key: postmanAuthVar, Value: "", Type: "string"},
+        },
+    }
+
+    folders := map[string]*postmanItem{}
+    for _, t := range doc.Tags {
+        folders[t.Name] = &postmanItem{
+            Name:        t.Name,
+            Description: t.Description,
+        }
+    }
+
+    walkOperations(
+        doc,
+        func(method, path string, op *spec.Operation, commonParams []spec.Parameter) {
+            folderName := "default"
+            if len(op.Tags) > 0 {
+                folderName = op.Tags[0]
+            }
+            folder, ok := folders[folderName]
+            if !ok {
+                folder = &postmanItem{Name: folderName}
+                folders[folderName] = folder
+            }
+
+            params := append(append([]spec.Parameter{}, commonParams...), op.Parameters...)
+            folder.Item = append(folder.Item, postmanRequestItem(method, path, op, params, doc.Definitions))
+        },
+    )
+
+    names := make([]string, 0, len(folders))
+    for name := range folders {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        if len(folders[name].Item) == 0 {
+            continue
+        }
+        c.Item = append(c.Item, *folders[name])
+    }
+
+    return c
+}
+
+func postmanRequestItem(
+    method, path string, op *spec.Operation, params []spec.Parameter, defs spec.Definitions,
+) postmanItem {
+    name := op.ID
+    if name == "" {
+        name = fmt.Sprintf("%s %s", method, path)
+    }
+
+    u := &postmanURL{
+        Host: []string{fmt.Sprintf("{{%s}}", postmanBaseURLVar)},
+        Path: strings.Split(strings.Trim(ronyPath(path), "/"), "/"),
+    }
+
+    req := &postmanRequest{
+        Method: method,
+        Header: []postmanKV{
+            {Key: "Content-Type", Value: "application/json"},
+        },
+        URL:         u,
+        Description: op.Description,
+    }
+
+    for _, p := range params {
+        switch p.In {
+        case "path":
+            u.Variable = append(
+                u.Variable,
+                postmanKV{Key: p.Name, Value: postmanValue(paramExample(p, defs))},
+            )
+        case "query":
+            for _, v := range postmanQueryValues(p, paramExample(p, defs)) {
+                u.Query = append(
+                    u.Query,
+                    postmanKV{
+                        Key:      p.Name,
+                        Value:    v,
+                        Disabled: !p.Required,
+                    },
+                )
+            }
+        case "header":
+            req.Header = append(req.Header, postmanKV{Key: p.Name})
+        case "body":
+            body, _ := json.MarshalIndent(paramExample(p, defs), "", "  ")
+            req.Body = &postmanBody{
+                Mode: "raw",
+                Raw:  string(body),
+                Options: map[string]interface{}{
+                    "raw": map[string]string{"language": "json"},
+                },
+            }
+        }
+    }
+
+    raw := strings.Join(append(u.Host, u.Path...), "/")
+    var query []string
+    for _, q := range u.Query {
+        if !q.Disabled {
+            query = append(query, fmt.Sprintf("%s=%s", q.Key, q.Value))
+        }
+    }
+    if len(query) > 0 {
+        raw = fmt.Sprintf("%s?%s", raw, strings.Join(query, "&"))
+    }
+    u.Raw = raw
+
+    return postmanItem{
+        Name:    name,
+        Request: req,
+    }
+}
+
+// paramExample returns the registered example of the param if exists, otherwise builds
+// one from its schema.
+func paramExample(p spec.Parameter, defs spec.Definitions) interface{} {
+    switch p.In {
+    case "body":
+        if ex, ok := p.Extensions[examplesExtension].(map[string]interface{}); ok {
+            if v, ok := ex[jsonMediaType]; ok {
+                return v
+            }
+        }
+
+        return exampleOf(p.Schema, defs, map[string]bool{})
+    default:
+        if v, ok := p.Extensions[exampleExtension]; ok {
+            return v
+        }
+
+        return exampleOf(paramSchema(p), defs, map[string]bool{})
+    }
+}
+
+// paramSchema returns the schema of a non-body parameter.
+func paramSchema(p spec.Parameter) *spec.Schema {
+    if p.Schema != nil {
+        return p.Schema
+    }
+
+    s := &spec.Schema{}
+    s.Typed(p.Type, p.Format)
+    s.Enum = p.Enum
+    if p.Items != nil {
+        s.Items = &spec.SchemaOrArray{Schema: &spec.Schema{}}
+        s.Items.Schema.Typed(p.Items.Type, p.Items.Format)
+    }
+
+    return s
+}
+
+// postmanQueryValues formats the example value of a query param. Array values are joined by the
+// separator of the collection format, or repeated if the format is multi.
+func postmanQueryValues(p spec.Parameter, v interface{}) []string {
+    arr, ok := v.([]interface{})
+    if !ok || p.Type != "array" {
+        return []string{postmanValue(v)}
+    }
+
+    values := make([]string, 0, len(arr))
+    for _, item := range arr {
+        values = append(values, fmt.Sprint(item))
+    }
+    if p.CollectionFormat == "multi" && len(values) > 0 {
+        return values
+    }
+
+    sep, ok := collectionSeps[p.CollectionFormat]
+    if !ok {
+        sep = ","
+    }
+
+    return []string{strings.Join(values, sep)}
+}
+
+// postmanValue formats the example value of a parameter, for arrays the first item is used.
+func postmanValue(v interface{}) string {
+    if arr, ok := v.([]interface{}); ok {
+        if len(arr) == 0 {
+            return ""
+        }
+        v = arr[0]
+    }
+
+    return fmt.Sprint(v)
+}
+
+type postmanCollection struct {
+    Info     postmanInfo   `json:"info"`
+    Item     []postmanItem `json:"item"`
+    Auth     *postmanAuth  `json:"auth,omitempty"`
+    Variable []postmanKV   `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+    Name   string `json:"name"`
+    Schema string `json:"schema"`
+}
+
+type postmanItem struct {
+    Name        string          `json:"name"`
+    Description string          `json:"description,omitempty"`
+    Item        []postmanItem   `json:"item,omitempty"`
+    Request     *postmanRequest `json:"request,omitempty"`
+}
+
+type postmanRequest struct {
+    Method      string       `json:"method"`
+    Header      []postmanKV  `json:"header"`
+    URL         *postmanURL  `json:"url"`
+    Body        *postmanBody `json:"body,omitempty"`
+    Description string       `json:"description,omitempty"`
+}
+
+type postmanURL struct {
+    Raw      string      `json:"raw"`
+    Host     []string    `json:"host"`
+    Path     []string    `json:"path"`
+    Query    []postmanKV `json:"query,omitempty"`
+    Variable []postmanKV `json:"variable,omitempty"`
+}
+
+type postmanBody struct {
+    Mode    string                 `json:"mode"`
+    Raw     string                 `json:"raw"`
+    Options map[string]interface{} `json:"options,omitempty"`
+}
+
+type postmanAuth struct {
+    Type   string      `json:"type"`
+    Bearer []postmanKV `json:"bearer,omitempty"`
+}
+
+type postmanKV struct {
+    Key      string `json:"key"`
+    Value    string `json:"value"`
+    Type     string `json:"type,omitempty"`
+    Disabled bool   `json:"disabled,omitempty"`
+}
@@ -0,0 +1,89 @@
+package swagger_test
+
+import (
+    "errors"
+    "strings"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/swagger"
+    "github.com/goccy/go-json"
+)
+
+func TestPostman(t *testing.T) {
+    sb := &strings.Builder{}
+    err := swagger.NewPostman("TestCollection").WriteTo(sb, testService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    collection := struct {
+        Info struct {
+            Name   string `json:"name"`
+            Schema string `json:"schema"`
+        } `json:"info"`
+        Item []struct {
+            Name string `json:"name"`
+            Item []struct {
+                Request struct {
+                    Method string `json:"method"`
+                    URL    struct {
+                        Raw      string `json:"raw"`
+                        Variable []struct {
+                            Key string `json:"key"`
+                        } `json:"variable"`
+                    } `json:"url"`
+                    Body *struct {
+                        Raw string `json:"raw"`
+                    } `json:"body"`
+                } `json:"request"`
+            } `json:"item"`
+        } `json:"item"`
+    }{}
+    err = json.Unmarshal([]byte(sb.String()), &collection)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if collection.Info.Name != "TestCollection" {
+        t.Fatalf("unexpected collection name: %s", collection.Info.Name)
+    }
+    if len(collection.Item) != 1 || collection.Item[0].Name != "testService" {
+        t.Fatalf("expected one folder for testService: %s", sb.String())
+    }
+
+    items := collection.Item[0].Item
+    if len(items) != 2 {
+        t.Fatalf("expected two requests, got %d", len(items))
+    }
+    for _, item := range items {
+        if item.Request.URL.Raw[:len("{{baseUrl}}/some/:x/:y")] != "{{baseUrl}}/some/:x/:y" {
+            t.Errorf("unexpected url: %s", item.Request.URL.Raw)
+        }
+        if len(item.Request.URL.Variable) != 2 {
+            t.Errorf("expected two path variables: %v", item.Request.URL.Variable)
+        }
+        switch item.Request.Method {
+        case "GET":
+            if item.Request.Body != nil {
+                t.Errorf("unexpected body for GET request")
+            }
+        case "POST":
+            if item.Request.Body == nil || !strings.Contains(item.Request.Body.Raw, `"w": [`) {
+                t.Errorf("expected example body for POST request: %v", item.Request.Body)
+            }
+        }
+    }
+}
+
+func TestPostmanValidation(t *testing.T) {
+    err := swagger.NewPostman("RawCollection").WriteTo(&strings.Builder{}, rawService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var verr *swagger.ValidationError
+    err = swagger.NewPostman("").WriteTo(&strings.Builder{}, invalidService{})
+    if !errors.As(err, &verr) {
+        t.Fatalf("expected validation error, got: %v", err)
+    }
+}
@@ -1,26 +1,57 @@
 package swagger
 
 import (
+    "encoding"
+    "encoding/json"
     "fmt"
     "io"
     "net/http"
-    "os"
     "reflect"
+    "sort"
+    "strconv"
     "strings"
+    "time"
 
     "github.com/clubpay/ronykit"
     "github.com/clubpay/ronykit/desc"
     "github.com/go-openapi/spec"
 )
 
+const wildcardExtension = "x-wildcard"
+
+var (
+    rawMessageType    = reflect.TypeOf(ronykit.RawMessage{})
+    timeType          = reflect.TypeOf(time.Time{})
+    textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+    jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
 type swaggerGen struct {
-    s       *spec.Swagger
-    tagName string
+    s                *spec.Swagger
+    tagName          string
+    queryTag         string
+    pathTag          string
+    headerTag        string
+    collectionFormat string
+    filters          []Filter
+    serviceTags      map[string][]string
+    ext              extensions
+    warnings         bool
+    onWarning        func(err error)
+    examples         map[contractKey]contractExample
+    errItems         map[string]map[string][]string
+    visited          map[reflect.Type]struct{}
 }
 
 func NewSwagger(title, ver, desc string) *swaggerGen {
     sg := &swaggerGen{
-        s: &spec.Swagger{},
+        s:                &spec.Swagger{},
+        collectionFormat: "csv",
+        serviceTags:      map[string][]string{},
+        ext:              newExtensions(),
+        examples:         map[contractKey]contractExample{},
+        errItems:         map[string]map[string][]string{},
+        visited:          map[reflect.Type]struct{}{},
     }
     sg.s.Info = &spec.Info{
         InfoProps: spec.InfoProps{
@@ -41,36 +72,204 @@ func (sg *swaggerGen) WithTag(tagName string) *swaggerGen {
     return sg
 }
 
+// WithQueryTag sets the struct tag key, which names the query params. Once it is set, only the
+// fields with this tag are added as query params, otherwise all the fields of the input which
+// are not path params are added, named by the tag set by WithTag.
+func (sg *swaggerGen) WithQueryTag(tagName string) *swaggerGen {
+    sg.queryTag = tagName
+
+    return sg
+}
+
+// WithPathTag sets the struct tag key, which names the path params. Fields without this tag
+// are matched with the path params by the tag set by WithTag.
+func (sg *swaggerGen) WithPathTag(tagName string) *swaggerGen {
+    sg.pathTag = tagName
+
+    return sg
+}
+
+// WithHeaderTag sets the struct tag key, which names the header params. Header params are only
+// added if it is set.
+func (sg *swaggerGen) WithHeaderTag(tagName string) *swaggerGen {
+    sg.headerTag = tagName
+
+    return sg
+}
+
+// WithCollectionFormat sets the default format of the array params, which is one of csv, ssv,
+// tsv, pipes or multi. It could be overridden for each field by the collectionFormat swag tag.
+// Path params never use multi, and fall back to csv.
+func (sg *swaggerGen) WithCollectionFormat(format string) *swaggerGen {
+    sg.collectionFormat = format
+
+    return sg
+}
+
+// WithFilter adds filters, which every route must pass to be added to the document.
+func (sg *swaggerGen) WithFilter(filters ...Filter) *swaggerGen {
+    sg.filters = append(sg.filters, filters...)
+
+    return sg
+}
+
+// WithServiceTags adds tags to the operations of the service, in addition to the service name.
+// The tags could be used by TagFilter to group the routes of different services.
+func (sg *swaggerGen) WithServiceTags(serviceName string, tags ...string) *swaggerGen {
+    sg.serviceTags[serviceName] = append(sg.serviceTags[serviceName], tags...)
+
+    return sg
+}
+
+// WithExample registers concrete example values for the input and output of the contract
+// with the name contractName in the service. Either of them could be nil. Unnamed contracts are
+// named by their index in the service, e.g. "0", as in their ronykit contract id.
+func (sg *swaggerGen) WithExample(serviceName, contractName string, in, out ronykit.Message) *swaggerGen {
+    sg.examples[contractKey{service: serviceName, contract: contractName}] = contractExample{
+        in:  in,
+        out: out,
+    }
+
+    return sg
+}
+
 func (sg swaggerGen) WriteToFile(filename string, services ...desc.ServiceDesc) error {
-    f, err := os.Create(filename)
+    return writeToFile(filename, func(w io.Writer) error { return sg.WriteTo(w, services...) })
+}
+
+func (sg swaggerGen) WriteTo(w io.Writer, descs ...desc.ServiceDesc) error {
+    if err := sg.build(descs...); err != nil {
+        return err
+    }
+
+    swaggerJSON, err := sg.s.MarshalJSON()
     if err != nil {
         return err
     }
 
-    return sg.WriteTo(f, services...)
+    _, err = w.Write(swaggerJSON)
+
+    return err
 }
 
-func (sg swaggerGen) WriteTo(w io.Writer, descs ...desc.ServiceDesc) error {
+// build adds the services to the document and validates it. Validation errors are returned,
+// unless they are downgraded to warnings by WithWarnings.
+func (sg swaggerGen) build(descs ...desc.ServiceDesc) error {
+    if err := sg.addServices(descs...); err != nil {
+        return err
+    }
+    if verr := validate(sg.s); verr != nil {
+        if !sg.warnings {
+            return verr
+        }
+        if sg.onWarning != nil {
+            sg.onWarning(verr)
+        }
+    }
+
+    return nil
+}
+
+func (sg swaggerGen) addServices(descs ...desc.ServiceDesc) error {
+    contracts := map[contractKey]bool{}
     for _, d := range descs {
         s := d.Desc()
-        addSwaggerTag(sg.s, s)
-        for _, c := range s.Contracts {
+        tagAdded := false
+        for idx, c := range s.Contracts {
+            key := newContractKey(s.Name, c, idx)
+            contracts[key] = true
             c.PossibleErrors = append(c.PossibleErrors, s.PossibleErrors...)
-            sg.addOperation(sg.s, s.Name, c)
+            added, err := sg.addOperation(sg.s, key, c)
+            if err != nil {
+                return fmt.Errorf("service %s: %w", s.Name, err)
+            }
+            // Services are tagged only if any of their contracts passed the filters.
+            if added && !tagAdded {
+                addSwaggerTag(sg.s, s, sg.ext.tags[s.Name])
+                tagAdded = true
+            }
         }
     }
 
-    swaggerJSON, err := sg.s.MarshalJSON()
-    if err != nil {
-        return err
+    var unknown []string
+    for key := range sg.examples {
+        if !contracts[key] {
+            unknown = append(unknown, key.String())
+        }
     }
+    if len(unknown) > 0 {
+        sort.Strings(unknown)
 
-    _, err = w.Write(swaggerJSON)
+        return fmt.Errorf("examples of unknown contracts: %s", strings.Join(unknown, ", "))
+    }
+    for key := range sg.ext.ops {
+        if !contracts[key] {
+            unknown = append(unknown, key.String())
+        }
+    }
+    if len(unknown) > 0 {
+        sort.Strings(unknown)
 
-    return err
+        return fmt.Errorf("operation extensions of unknown contracts: %s", strings.Join(unknown, ", "))
+    }
+
+    sg.setErrorItems(sg.s)
+    addDefinitionExamples(sg.s)
+    addExtensions(&sg.s.Extensions, sg.ext.doc)
+
+    return nil
+}
+
+// contractKey identifies a contract by its service, since contract names are only unique in
+// their service.
+type contractKey struct {
+    service  string
+    contract string
 }
 
-func (sg swaggerGen) addOperation(swag *spec.Swagger, serviceName string, c desc.Contract) {
+// newContractKey returns the key of the contract with the index idx in the service. Unnamed
+// contracts are named by their index, as in their ronykit contract id.
+func newContractKey(serviceName string, c desc.Contract, idx int) contractKey {
+    name := c.Name
+    if name == "" {
+        name = strconv.Itoa(idx)
+    }
+
+    return contractKey{service: serviceName, contract: name}
+}
+
+func (k contractKey) String() string {
+    return fmt.Sprintf("%s.%s", k.service, k.contract)
+}
+
+// addOperation adds the REST routes of the contract which pass the filters. It reports if any
+// route is added.
+func (sg swaggerGen) addOperation(swag *spec.Swagger, key contractKey, c desc.Contract) (bool, error) {
+    serviceName := key.service
+    tags := append([]string{serviceName}, sg.serviceTags[serviceName]...)
+
+    var routes []ronykit.RESTRouteSelector
+    for _, sel := range c.RouteSelectors {
+        restSel, ok := sel.Selector.(ronykit.RESTRouteSelector)
+        if !ok {
+            continue
+        }
+
+        r := Route{
+            Service:  serviceName,
+            Tags:     tags,
+            Contract: c.Name,
+            Method:   restSel.GetMethod(),
+            Path:     restSel.GetPath(),
+        }
+        if sg.accept(r) {
+            routes = append(routes, restSel)
+        }
+    }
+    if len(routes) == 0 {
+        return false, nil
+    }
+
     if swag.Paths == nil {
         swag.Paths = &spec.Paths{
             Paths: map[string]spec.PathItem{},
@@ -80,43 +279,52 @@ func (sg swaggerGen) addOperation(swag *spec.Swagger, serviceName string, c desc
     inType := reflect.Indirect(reflect.ValueOf(c.Input)).Type()
     outType := reflect.Indirect(reflect.ValueOf(c.Output)).Type()
     opID := c.Name
-    op := spec.NewOperation(opID).
-        WithTags(serviceName).
+    baseOp := spec.NewOperation(opID).
+        WithTags(tags...).
         WithProduces("application/json").
         WithConsumes("application/json").
             RespondsWith(
                 http.StatusOK,
                 spec.NewResponse().
-                        WithSchema(
-                            spec.RefProperty(fmt.Sprintf("#/definitions/%s", outType.Name())),
-                        ),
+                        WithSchema(sg.messageSchema(swag, outType)),
             )
 
-    possibleErrors := map[int][]string{}
+    possibleErrors := map[int]*errorResponse{}
     for _, pe := range c.PossibleErrors {
         errType := reflect.Indirect(reflect.ValueOf(pe.Message)).Type()
         sg.addDefinition(swag, errType)
-        possibleErrors[pe.Code] = append(possibleErrors[pe.Code], pe.Item)
-        op.RespondsWith(
-            pe.Code,
+        sg.addErrorItem(pe)
+
+        er, ok := possibleErrors[pe.Code]
+        if !ok {
+            er = &errorResponse{example: pe.Message}
+            possibleErrors[pe.Code] = er
+        }
+        er.add(errType.Name(), pe.Item)
+    }
+    for code, er := range possibleErrors {
+        baseOp.RespondsWith(
+            code,
             spec.NewResponse().
-                    WithSchema(
-                        spec.RefProperty(fmt.Sprintf("#/definitions/%s", errType.Name())),
-                    ).
-                WithDescription(fmt.Sprintf("Items: %s", strings.Join(possibleErrors[pe.Code], ", "))),
+                WithSchema(er.schema()).
+                WithDescription(fmt.Sprintf("Items: %s", strings.Join(er.items, ", "))).
+                AddExample("application/json", marshalExample(er.example)),
         )
     }
-    for _, sel := range c.RouteSelectors {
-        restSel, ok := sel.Selector.(ronykit.RESTRouteSelector)
-        if !ok {
-            continue
+    for idx, restSel := range routes {
+        // Every route has its own copy of the operation, since their params differ, and the
+        // operation ids must be unique in the document.
+        op := routeOperation(baseOp, idx)
+        if err := sg.setInput(op, restSel.GetPath(), inType); err != nil {
+            return false, fmt.Errorf(
+                "contract %s, %s %s: %w", c.Name, restSel.GetMethod(), restSel.GetPath(), err,
+            )
         }
-
-        sg.setInput(op, restSel.GetPath(), inType)
         sg.addDefinition(swag, inType)
         sg.addDefinition(swag, outType)
 
-        restPath := replacePath(restSel.GetPath())
+        restPath := replacePath(docPath(swag, restSel.GetPath()))
+        op.ID = uniqueOperationID(swag, serviceName, op.ID, restSel.GetMethod(), restPath)
         pathItem := swag.Paths.Paths[restPath]
         switch strings.ToUpper(restSel.GetMethod()) {
         case http.MethodGet:
@@ -125,100 +333,302 @@ func (sg swaggerGen) addOperation(swag *spec.Swagger, serviceName string, c desc
             pathItem.Delete = op
         case http.MethodPost:
             op.AddParam(
-                spec.BodyParam(
-                    inType.Name(),
-                    spec.RefProperty(fmt.Sprintf("#/definitions/%s", inType.Name())),
-                ),
+                spec.BodyParam(inType.Name(), sg.messageSchema(swag, inType)),
             )
             pathItem.Post = op
         case http.MethodPut:
             op.AddParam(
-                spec.BodyParam(
-                    inType.Name(),
-                    spec.RefProperty(fmt.Sprintf("#/definitions/%s", inType.Name())),
-                ),
+                spec.BodyParam(inType.Name(), sg.messageSchema(swag, inType)),
             )
             pathItem.Put = op
         case http.MethodPatch:
             op.AddParam(
-                spec.BodyParam(
-                    inType.Name(),
-                    spec.RefProperty(fmt.Sprintf("#/definitions/%s", inType.Name())),
-                ),
+                spec.BodyParam(inType.Name(), sg.messageSchema(swag, inType)),
             )
             pathItem.Patch = op
         }
+        addExtensions(&op.Extensions, sg.ext.ops[key])
+        if ex, ok := sg.examples[key]; ok {
+            setOperationExamples(op, ex)
+        }
+
         swag.Paths.Paths[restPath] = pathItem
     }
+
+    return true, nil
+}
+
+// routeOperation returns a copy of the operation for the route with the given index. Routes
+// other than the first one get a numbered operation id.
+func routeOperation(op *spec.Operation, idx int) *spec.Operation {
+    rop := *op
+    rop.Parameters = nil
+    if idx > 0 && rop.ID != "" {
+        rop.ID = fmt.Sprintf("%s%d", rop.ID, idx+1)
+    }
+
+    return &rop
 }
 
-func (sg *swaggerGen) setInput(op *spec.Operation, path string, inType reflect.Type) {
+// uniqueOperationID returns an operation id for the route which is not used by the other
+// operations of the document. Clashing ids are qualified with the service name, and numbered if
+// they still clash, e.g. get --> users.get --> users.get2.
+func uniqueOperationID(swag *spec.Swagger, serviceName, id, method, path string) string {
+    if id == "" {
+        return ""
+    }
+
+    used := map[string]struct{}{}
+    for p, pathItem := range swag.Paths.Paths {
+        for m, op := range pathOperations(pathItem) {
+            if op != nil && (p != path || m != strings.ToUpper(method)) {
+                used[op.ID] = struct{}{}
+            }
+        }
+    }
+
+    if _, ok := used[id]; !ok {
+        return id
+    }
+    qualified := fmt.Sprintf("%s.%s", serviceName, id)
+    uid := qualified
+    for n := 2; ; n++ {
+        if _, ok := used[uid]; !ok {
+            return uid
+        }
+        uid = fmt.Sprintf("%s%d", qualified, n)
+    }
+}
+
+// pathOperations returns the operations of the path item by their method.
+func pathOperations(pathItem spec.PathItem) map[string]*spec.Operation {
+    return map[string]*spec.Operation{
+        http.MethodGet:    pathItem.Get,
+        http.MethodPost:   pathItem.Post,
+        http.MethodPut:    pathItem.Put,
+        http.MethodPatch:  pathItem.Patch,
+        http.MethodDelete: pathItem.Delete,
+    }
+}
+
+// setInput adds the fields of the input as the params of the operation. It returns an error if
+// any of the path params has no matching field in the input.
+func (sg *swaggerGen) setInput(op *spec.Operation, path string, inType reflect.Type) error {
+    pathParams, err := getPathParams(path)
+    if err != nil {
+        return err
+    }
+
     if inType.Kind() == reflect.Ptr {
         inType = inType.Elem()
     }
     if inType.Kind() != reflect.Struct {
-        return
-    }
-
-    var pathParams = make([]string, 0)
-    for _, pp := range strings.Split(path, "/") {
-        if !strings.HasPrefix(pp, ":") {
-            continue
+        if len(pathParams) > 0 {
+            return fmt.Errorf("path param %q has no matching field in %s", pathParams[0], inType)
         }
-        pathParam := strings.TrimPrefix(pp, ":")
-        pathParams = append(pathParams, pathParam)
+
+        return nil
     }
 
+    matched := map[string]bool{}
     queue := []reflect.Type{inType}
     for j := 0; j < len(queue); j++ {
         inType := queue[j]
         for i := 0; i < inType.NumField(); i++ {
-            if inType.Field(i).Anonymous {
-                queue = append(queue, inType.Field(i).Type)
+            f := inType.Field(i)
+            if f.Anonymous {
+                queue = append(queue, indirect(f.Type))
 
                 continue
             }
-            pt := getParsedStructTag(inType.Field(i).Tag, sg.tagName)
-            if pt.Name == "" {
-                continue
+            // Fields which are skipped in the body, could still be params by their own tags.
+            pt, ok := parseField(f, sg.tagName)
+            if !ok {
+                if f.PkgPath != "" {
+                    continue
+                }
+                pt.Name = ""
+            }
+
+            pathName := pt.Name
+            if name, ok := lookupFieldName(f, sg.pathTag); ok {
+                pathName = name
             }
-            found := false
+            // Fields are matched case-insensitively, but the param is named as in the route,
+            // so it matches the template of the path.
+            routeParam := ""
             for _, pathParam := range pathParams {
-                if strings.ToLower(pt.Name) == strings.ToLower(pathParam) {
-                    found = true
+                if pathName != "" && strings.EqualFold(pathName, pathParam) {
+                    routeParam = pathParam
+                    matched[pathParam] = true
                 }
             }
+            found := routeParam != ""
 
-            switch {
+            var in *spec.Parameter
+            switch headerName, isHeader := lookupFieldName(f, sg.headerTag); {
             case found:
-                op.AddParam(
-                    setSwaggerParam(
-                        spec.PathParam(pt.Name),
-                        inType.Field(i).Type,
-                        pt.Optional,
-                    ),
-                )
+                in = spec.PathParam(routeParam)
+                // Wildcard params match the rest of the path, which the path template could
+                // not tell.
+                if isWildcard(path, routeParam) {
+                    in.AddExtension(wildcardExtension, true)
+                }
+            case isHeader:
+                in = spec.HeaderParam(headerName)
             default:
-                op.AddParam(
-                    setSwaggerParam(
-                        spec.QueryParam(pt.Name),
-                        inType.Field(i).Type,
-                        pt.Optional,
-                    ),
-                )
+                queryName, ok := sg.queryParamName(f, pt)
+                if !ok {
+                    continue
+                }
+                if fType := indirect(f.Type); isObject(fType) {
+                    sg.addStructParams(op, queryName, fType, pt.Optional, map[reflect.Type]bool{inType: true})
+
+                    continue
+                }
+                if indirect(f.Type).Kind() == reflect.Map {
+                    addMapParamNote(op, queryName)
+
+                    continue
+                }
+
+                in = spec.QueryParam(queryName)
+            }
+
+            // Types which could not be described as a param, are not added to the operation.
+            if p := sg.setSwaggerParam(in, f.Type, pt); p != nil {
+                op.AddParam(p)
+            } else if found {
+                return fmt.Errorf("path param %q has unsupported type %s", routeParam, f.Type)
             }
         }
     }
 
+    for _, pathParam := range pathParams {
+        if !matched[pathParam] {
+            return fmt.Errorf("path param %q has no matching field in %s", pathParam, inType.Name())
+        }
+    }
+
+    return nil
+}
+
+// queryParamName returns the name of the field f as a query param. If the query tag is not set,
+// the field is named the same as in the body.
+func (sg *swaggerGen) queryParamName(f reflect.StructField, pt parsedStructTag) (string, bool) {
+    if sg.queryTag == "" {
+        return pt.Name, pt.Name != ""
+    }
+
+    return lookupFieldName(f, sg.queryTag)
+}
+
+// getPathParams returns the names of the params of the path in the ronykit mux format, i.e. the
+// named segments like :id and the wildcard segments like *filepath.
+func getPathParams(path string) ([]string, error) {
+    var params []string
+    for _, seg := range strings.Split(path, "/") {
+        if !strings.HasPrefix(seg, ":") && !strings.HasPrefix(seg, "*") {
+            continue
+        }
+        if len(seg) == 1 {
+            return nil, fmt.Errorf("path segment %q must be named, e.g. %sname", seg, seg)
+        }
+
+        params = append(params, seg[1:])
+    }
+
+    return params, nil
+}
+
+// isWildcard reports if the param of the path in the ronykit mux format is a wildcard, e.g.
+// filepath in /files/*filepath.
+func isWildcard(path, param string) bool {
+    for _, seg := range strings.Split(path, "/") {
+        if seg == "*"+param {
+            return true
+        }
+    }
+
+    return false
+}
+
+// addStructParams adds the fields of the struct t as query params, which are named by their
+// path from the input, e.g. filter.status. This is the Swagger 2.0 counterpart of the
+// deepObject style of OpenAPI 3.
+func (sg *swaggerGen) addStructParams(
+    op *spec.Operation, prefix string, t reflect.Type, optional bool, visited map[reflect.Type]bool,
+) {
+    if visited[t] {
+        return
+    }
+    visited[t] = true
+    defer delete(visited, t)
+
+    queue := []reflect.Type{t}
+    for j := 0; j < len(queue); j++ {
+        t := queue[j]
+        for i := 0; i < t.NumField(); i++ {
+            f := t.Field(i)
+            if f.Anonymous {
+                queue = append(queue, indirect(f.Type))
+
+                continue
+            }
+            pt, ok := parseField(f, sg.tagName)
+            if !ok {
+                continue
+            }
+            name, ok := sg.queryParamName(f, pt)
+            if !ok {
+                continue
+            }
+            pt.Name = fmt.Sprintf("%s.%s", prefix, name)
+            pt.Optional = pt.Optional || optional
+
+            if fType := indirect(f.Type); isObject(fType) {
+                sg.addStructParams(op, pt.Name, fType, pt.Optional, visited)
+
+                continue
+            }
+            if indirect(f.Type).Kind() == reflect.Map {
+                addMapParamNote(op, pt.Name)
+
+                continue
+            }
+            if p := sg.setSwaggerParam(spec.QueryParam(pt.Name), f.Type, pt); p != nil {
+                op.AddParam(p)
+            }
+        }
+    }
+}
+
+// addMapParamNote describes the map query param in the description of the operation. Swagger 2.0
+// has no object params, hence the param itself is left out of the operation, rather than being
+// described with a wrong type.
+func addMapParamNote(op *spec.Operation, name string) {
+    if op.Description != "" {
+        op.Description += "\n\n"
+    }
+    op.Description += fmt.Sprintf(
+        "The %s query param is a map, which is sent as %s[key]=value for each entry.", name, name,
+    )
 }
 
 func (sg *swaggerGen) addDefinition(swag *spec.Swagger, rType reflect.Type) {
     if rType.Kind() == reflect.Ptr {
         rType = rType.Elem()
     }
-    if rType.Kind() != reflect.Struct {
+    if !isObject(rType) {
+        return
+    }
+
+    // We mark the type as visited before adding its fields, hence recursive types
+    // only refer to their definition instead of adding it again.
+    if _, ok := sg.visited[rType]; ok {
         return
     }
+    sg.visited[rType] = struct{}{}
 
     if swag.Definitions == nil {
         swag.Definitions = map[string]spec.Schema{}
@@ -227,152 +637,154 @@ func (sg *swaggerGen) addDefinition(swag *spec.Swagger, rType reflect.Type) {
     def := spec.Schema{}
     def.Typed("object", "")
 
+    defName := rType.Name()
     queue := []reflect.Type{rType}
     for j := 0; j < len(queue); j++ {
         rType := queue[j]
         for i := 0; i < rType.NumField(); i++ {
             f := rType.Field(i)
             if f.Anonymous {
-                queue = append(queue, f.Type)
+                queue = append(queue, indirect(f.Type))
                 continue
             }
             fType := f.Type
-            pt := getParsedStructTag(f.Tag, sg.tagName)
-            if pt.Name == "" {
+            pt, ok := parseField(f, sg.tagName)
+            if !ok {
                 continue
             }
 
-            var wrapFuncChain schemaWrapperChain
-            switch fType.Kind() {
-            case reflect.Ptr:
-                fType = fType.Elem()
-                wrapFuncChain.Add(
-                    func(schema *spec.Schema) *spec.Schema {
-                        return schema
-                    },
-                )
-            case reflect.Slice:
-                fType = fType.Elem()
-                wrapFuncChain.Add(
-                    func(schema *spec.Schema) *spec.Schema {
-                        return spec.ArrayProperty(schema)
-                    },
-                )
-            default:
-                wrapFuncChain.Add(
-                    func(schema *spec.Schema) *spec.Schema {
-                        return schema
-                    },
-                )
+            schema := sg.typeSchema(swag, fType)
+            if pt.Example != "" {
+                schema.WithExample(typedExample(pt.Example, schema))
             }
-
             if len(pt.PossibleValues) > 0 {
-                wrapFuncChain.Add(
-                    func(schema *spec.Schema) *spec.Schema {
-                        for _, v := range pt.PossibleValues {
-                            schema.Enum = append(schema.Enum, v)
-                        }
-
-                        return schema
-                    },
-                )
+                items := itemsSchema(schema)
+                for _, v := range pt.PossibleValues {
+                    items.Enum = append(items.Enum, typedExample(v, items))
+                }
             }
 
-        Switch:
-            switch fType.Kind() {
-            case reflect.String:
-                def.SetProperty(pt.Name, wrapFuncChain.Apply(spec.StringProperty()))
-            case reflect.Int8, reflect.Uint8:
-                def.SetProperty(pt.Name, wrapFuncChain.Apply(spec.ArrayProperty(spec.Int8Property())))
-            case reflect.Int32, reflect.Uint32:
-                def.SetProperty(pt.Name, wrapFuncChain.Apply(spec.Int32Property()))
-            case reflect.Int, reflect.Uint, reflect.Int64, reflect.Uint64:
-                def.SetProperty(pt.Name, wrapFuncChain.Apply(spec.Int64Property()))
-            case reflect.Float32:
-                def.SetProperty(pt.Name, wrapFuncChain.Apply(spec.Float32Property()))
-            case reflect.Float64:
-                def.SetProperty(pt.Name, wrapFuncChain.Apply(spec.Float64Property()))
-            case reflect.Struct:
-                def.SetProperty(pt.Name, wrapFuncChain.Apply(spec.RefProperty(fmt.Sprintf("#/definitions/%s", fType.Name()))))
-                sg.addDefinition(swag, fType)
-            case reflect.Bool:
-                def.SetProperty(pt.Name, wrapFuncChain.Apply(spec.BoolProperty()))
-            case reflect.Interface, reflect.Map:
-                sub := &spec.Schema{}
-                sub.Typed("object", "")
-                def.SetProperty(pt.Name, wrapFuncChain.Apply(sub))
-            case reflect.Ptr:
-                fType = fType.Elem()
-
-                goto Switch
-            default:
-                def.SetProperty(pt.Name, wrapFuncChain.Apply(spec.StringProperty()))
-            }
-        }
+            addExtensions(&schema.Extensions, pt.Extensions)
+            addExtensions(&schema.Extensions, sg.ext.props[defName][pt.Name])
 
-        swag.Definitions[rType.Name()] = def
+            def.SetProperty(pt.Name, *schema)
+        }
     }
 
+    swag.Definitions[defName] = def
 }
 
-func addSwaggerTag(swag *spec.Swagger, s *desc.Service) {
-    swag.Tags = append(
-        swag.Tags,
-        spec.NewTag(s.Name, s.Description, nil),
-    )
+func addSwaggerTag(swag *spec.Swagger, s *desc.Service, ext spec.Extensions) {
+    tag := spec.NewTag(s.Name, s.Description, nil)
+    addExtensions(&tag.Extensions, ext)
+    swag.Tags = append(swag.Tags, tag)
 }
 
-func setSwaggerParam(p *spec.Parameter, t reflect.Type, optional bool) *spec.Parameter {
-    if optional {
+// setSwaggerParam sets the type of the param based on t. It returns nil if the type could not
+// be described as a non-body param.
+func (sg *swaggerGen) setSwaggerParam(p *spec.Parameter, t reflect.Type, pt parsedStructTag) *spec.Parameter {
+    if pt.Optional {
         p.AsOptional()
     } else {
         p.AsRequired()
     }
+
+    t = indirect(t)
+    if format, ok := textFormat(t); ok {
+        p.Typed("string", format)
+
+        return p
+    }
+
     kind := t.Kind()
     switch kind {
-    case reflect.Map:
-        p.Typed("object", "")
-    case reflect.Slice:
-        switch t.Elem().Kind() {
-        case reflect.String:
-            p.Typed("string", kind.String())
-        case reflect.Float64, reflect.Float32:
-            p.Typed("number", kind.String())
-        case reflect.Int8, reflect.Uint8:
-            p.Typed("integer", "int8")
-        case reflect.Int32, reflect.Uint32:
-            p.Typed("integer", "int32")
-        case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
-            p.Typed("integer", "int64")
-        default:
+    case reflect.Slice, reflect.Array:
+        if kind == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+            p.Typed("string", "byte")
+
+            break
+        }
+
+        items := paramItems(t.Elem())
+        if items == nil {
             return nil
         }
-    case reflect.String:
-        p.Typed("string", kind.String())
-    case reflect.Float64, reflect.Float32:
-        p.Typed("number", kind.String())
-    case reflect.Int8, reflect.Uint8:
-        p.Typed("integer", "int8")
-    case reflect.Int32, reflect.Uint32:
-        p.Typed("integer", "int32")
-    case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
-        p.Typed("integer", "int64")
+
+        format := sg.collectionFormat
+        if pt.CollectionFormat != "" {
+            format = pt.CollectionFormat
+        }
+        // multi is only valid for query and formData params.
+        if format == "multi" && p.In != "query" && p.In != "formData" {
+            format = "csv"
+        }
+        p.CollectionOf(items, format)
+        if kind == reflect.Array {
+            p.WithMinItems(int64(t.Len())).WithMaxItems(int64(t.Len()))
+        }
     default:
-        return nil
+        typ, format, ok := paramType(t)
+        if !ok {
+            return nil
+        }
+
+        p.Typed(typ, format)
+        if isUnsigned(kind) {
+            p.WithMinimum(0, false)
+        }
     }
 
     return p
 }
 
+// paramItems returns the items of an array param, whose elements are of the type t. It returns
+// nil if t is not a primitive type.
+func paramItems(t reflect.Type) *spec.Items {
+    t = indirect(t)
+    typ, format, ok := paramType(t)
+    if !ok {
+        return nil
+    }
+
+    items := spec.NewItems().Typed(typ, format)
+    if isUnsigned(t.Kind()) {
+        items.WithMinimum(0, false)
+    }
+
+    return items
+}
+
+// paramType returns the swagger type and format of the primitive type t.
+func paramType(t reflect.Type) (string, string, bool) {
+    if format, ok := textFormat(t); ok {
+        return "string", format, true
+    }
+
+    switch kind := t.Kind(); kind {
+    case reflect.String:
+        return "string", kind.String(), true
+    case reflect.Bool:
+        return "boolean", "", true
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+        reflect.Float32, reflect.Float64:
+        typ, format := numberFormat(kind)
+
+        return typ, format, true
+    default:
+        return "", "", false
+    }
+}
+
 // replacePath converts the ronykit mux format urls to swagger url format.
-// e.g. /some/path/:x1 --> /some/path/{x1}
+// e.g. /some/path/:x1 --> /some/path/{x1} and /files/*filepath --> /files/{filepath}
 func replacePath(path string) string {
     sb := strings.Builder{}
     for idx, p := range strings.Split(path, "/") {
         if idx > 0 {
             sb.WriteRune('/')
         }
-        if strings.HasPrefix(p, ":") {
+        if strings.HasPrefix(p, ":") || strings.HasPrefix(p, "*") {
             sb.WriteRune('{')
             sb.WriteString(p[1:])
             sb.WriteRune('}')
@@ -384,20 +796,136 @@ func replacePath(path string) string {
     return sb.String()
 }
 
-type schemaWrapper func(schema *spec.Schema) *spec.Schema
+// messageSchema returns the schema of the input or output message of a contract. Raw messages
+// could be any JSON value, hence their schema is empty.
+func (sg *swaggerGen) messageSchema(swag *spec.Swagger, t reflect.Type) *spec.Schema {
+    if t == rawMessageType {
+        return &spec.Schema{}
+    }
 
-type schemaWrapperChain []schemaWrapper
+    return sg.typeSchema(swag, t)
+}
 
-func (chain *schemaWrapperChain) Add(w schemaWrapper) schemaWrapperChain {
-    *chain = append(*chain, w)
+// typeSchema returns the schema of the type t. Pointers are dereferenced, and slices, arrays
+// and maps are described recursively, so any nesting of them is supported. Types which are
+// marshaled as text, e.g. time.Time, are described as strings.
+func (sg *swaggerGen) typeSchema(swag *spec.Swagger, t reflect.Type) *spec.Schema {
+    if format, ok := textFormat(t); ok {
+        return (&spec.Schema{}).Typed("string", format)
+    }
 
-    return *chain
+    switch t.Kind() {
+    case reflect.Ptr:
+        return sg.typeSchema(swag, t.Elem())
+    case reflect.Slice:
+        // encoding/json marshals []byte as a base64 encoded string.
+        if t.Elem().Kind() == reflect.Uint8 {
+            return (&spec.Schema{}).Typed("string", "byte")
+        }
+
+        return spec.ArrayProperty(sg.typeSchema(swag, t.Elem()))
+    case reflect.Array:
+        return spec.ArrayProperty(sg.typeSchema(swag, t.Elem())).
+            WithMinItems(int64(t.Len())).
+            WithMaxItems(int64(t.Len()))
+    case reflect.Map:
+        return spec.MapProperty(sg.typeSchema(swag, t.Elem()))
+    case reflect.Interface:
+        return (&spec.Schema{}).Typed("object", "")
+    case reflect.Struct:
+        sg.addDefinition(swag, t)
+
+        return spec.RefProperty(fmt.Sprintf("#/definitions/%s", t.Name()))
+    case reflect.String:
+        return spec.StringProperty()
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+        reflect.Float32, reflect.Float64:
+        typ, format := numberFormat(t.Kind())
+        schema := (&spec.Schema{}).Typed(typ, format)
+        if isUnsigned(t.Kind()) {
+            schema.WithMinimum(0, false)
+        }
+
+        return schema
+    case reflect.Bool:
+        return spec.BoolProperty()
+    default:
+        return spec.StringProperty()
+    }
+}
+
+// numberFormat returns the swagger type and format of the numeric kind k.
+func numberFormat(k reflect.Kind) (string, string) {
+    switch k {
+    case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        return "integer", k.String()
+    case reflect.Int:
+        return "integer", "int64"
+    case reflect.Uint:
+        return "integer", "uint64"
+    case reflect.Float32:
+        return "number", "float"
+    default:
+        return "number", "double"
+    }
 }
 
-func (chain schemaWrapperChain) Apply(schema *spec.Schema) spec.Schema {
-    for _, w := range chain {
-        schema = w(schema)
+func isUnsigned(k reflect.Kind) bool {
+    switch k {
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        return true
+    default:
+        return false
+    }
+}
+
+// itemsSchema returns the innermost items schema of the nested arrays, or s itself if it
+// is not an array.
+func itemsSchema(s *spec.Schema) *spec.Schema {
+    for s.Type.Contains("array") && s.Items != nil && s.Items.Schema != nil {
+        s = s.Items.Schema
+    }
+
+    return s
+}
+
+func indirect(t reflect.Type) reflect.Type {
+    for t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+
+    return t
+}
+
+// isObject reports whether t is a struct which is marshaled field by field, i.e. it is
+// described as an object rather than a string.
+func isObject(t reflect.Type) bool {
+    if t.Kind() != reflect.Struct {
+        return false
+    }
+    _, ok := textFormat(t)
+
+    return !ok
+}
+
+// textFormat reports whether the type t is marshaled as a JSON string by its MarshalText
+// method, and returns its swagger format, e.g. date-time for time.Time. Types which marshal
+// themselves to JSON are not known to be strings, hence they are not reported.
+func textFormat(t reflect.Type) (string, bool) {
+    t = indirect(t)
+    if t == timeType {
+        return "date-time", true
+    }
+    if t.Kind() == reflect.Interface {
+        return "", false
+    }
+
+    pt := reflect.PtrTo(t)
+    if pt.Implements(jsonMarshalerType) {
+        return "", false
     }
 
-    return *schema
+    return "", pt.Implements(textMarshalerType)
 }
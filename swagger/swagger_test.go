@@ -2,13 +2,18 @@ package swagger_test
 
 import (
     "fmt"
+    "net"
+    "regexp"
+    "sort"
     "strings"
     "testing"
+    "time"
 
     "github.com/clubpay/ronycontrib/swagger"
     "github.com/clubpay/ronykit"
     "github.com/clubpay/ronykit/desc"
     "github.com/clubpay/ronykit/std/gateway/fasthttp"
+    "github.com/go-openapi/spec"
     "github.com/goccy/go-json"
 )
 
@@ -100,3 +105,479 @@ func TestNewSwagger(t *testing.T) {
     x, _ := json.MarshalIndent(json.RawMessage(sb.String()), "", "   ")
     fmt.Println(string(x))
 }
+
+type treeNode struct {
+    Name     string     `json:"name"`
+    Parent   *treeNode  `json:"parent"`
+    Children []treeNode `json:"children"`
+    Owner    *owner     `json:"owner"`
+}
+
+type owner struct {
+    Name  string     `json:"name"`
+    Trees []treeNode `json:"trees"`
+    Self  *owner     `json:"self"`
+}
+
+type recursiveService struct{}
+
+func (recursiveService) Desc() *desc.Service {
+    return desc.NewService("recursiveService").
+        AddContract(
+            desc.NewContract().
+                AddSelector(fasthttp.POST("/tree")).
+                SetInput(&treeNode{}).
+                SetOutput(&owner{}),
+        )
+}
+
+func TestRecursiveTypes(t *testing.T) {
+    sb := &strings.Builder{}
+    err := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WriteTo(sb, recursiveService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    doc := struct {
+        Definitions map[string]struct {
+            Properties map[string]struct {
+                Ref   string `json:"$ref"`
+                Items struct {
+                    Ref string `json:"$ref"`
+                } `json:"items"`
+            } `json:"properties"`
+        } `json:"definitions"`
+    }{}
+    err = json.Unmarshal([]byte(sb.String()), &doc)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tree := doc.Definitions["treeNode"].Properties
+    if tree["parent"].Ref != "#/definitions/treeNode" ||
+        tree["children"].Items.Ref != "#/definitions/treeNode" ||
+        tree["owner"].Ref != "#/definitions/owner" {
+        t.Errorf("unexpected treeNode definition: %v", tree)
+    }
+    own := doc.Definitions["owner"].Properties
+    if own["trees"].Items.Ref != "#/definitions/treeNode" || own["self"].Ref != "#/definitions/owner" {
+        t.Errorf("unexpected owner definition: %v", own)
+    }
+
+    code := &strings.Builder{}
+    err = swagger.NewGoGen("api").WriteTo(code, []byte(sb.String()))
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, expected := range []string{`Parent\s+\*treeNode`, `Self\s+\*owner`} {
+        if !regexp.MustCompile(expected).MatchString(code.String()) {
+            t.Errorf("expected %q in generated code:\n%s", expected, code.String())
+        }
+    }
+}
+
+type nestedItem struct {
+    ID string `json:"id"`
+}
+
+type nestedReq struct {
+    Matrix   [][]string              `json:"matrix"`
+    Point    [3]int                  `json:"point"`
+    ItemsPtr *[]nestedItem           `json:"itemsPtr"`
+    PtrItems []*nestedItem           `json:"ptrItems"`
+    Grouped  map[string][]nestedItem `json:"grouped"`
+    Tags     []string                `json:"tags" swag:"enum:a,b"`
+}
+
+type nestedService struct{}
+
+func (nestedService) Desc() *desc.Service {
+    return desc.NewService("nestedService").
+        AddContract(
+            desc.NewContract().
+                AddSelector(fasthttp.POST("/nested")).
+                SetInput(&nestedReq{}).
+                SetOutput(&nestedItem{}),
+        )
+}
+
+func TestNestedTypes(t *testing.T) {
+    doc := generate(t, nestedService{})
+
+    props := doc.Definitions["nestedReq"].Properties
+    matrix := props["matrix"]
+    if !matrix.Type.Contains("array") || !matrix.Items.Schema.Type.Contains("array") ||
+        !matrix.Items.Schema.Items.Schema.Type.Contains("string") {
+        t.Errorf("unexpected matrix schema: %v", matrix)
+    }
+    point := props["point"]
+    if point.MinItems == nil || *point.MinItems != 3 || point.MaxItems == nil || *point.MaxItems != 3 {
+        t.Errorf("unexpected point schema: %v", point)
+    }
+    for _, name := range []string{"itemsPtr", "ptrItems"} {
+        if ref := props[name].Items.Schema.Ref.String(); ref != "#/definitions/nestedItem" {
+            t.Errorf("unexpected %s items ref: %s", name, ref)
+        }
+    }
+    grouped := props["grouped"].AdditionalProperties
+    if grouped == nil || grouped.Schema.Items.Schema.Ref.String() != "#/definitions/nestedItem" {
+        t.Errorf("unexpected grouped schema: %v", props["grouped"])
+    }
+    if tags := props["tags"]; len(tags.Items.Schema.Enum) != 2 {
+        t.Errorf("expected enum on tags items: %v", tags)
+    }
+}
+
+type numericReq struct {
+    I8    int8    `json:"i8"`
+    I16   int16   `json:"i16"`
+    I     int     `json:"i"`
+    U8    uint8   `json:"u8"`
+    U16   uint16  `json:"u16"`
+    U     uint    `json:"u"`
+    F32   float32 `json:"f32"`
+    Bytes []byte  `json:"bytes"`
+    Int8s []int8  `json:"int8s"`
+}
+
+type numericService struct{}
+
+func (numericService) Desc() *desc.Service {
+    return desc.NewService("numericService").
+        AddContract(
+            desc.NewContract().
+                AddSelector(fasthttp.POST("/numeric")).
+                SetInput(&numericReq{}).
+                SetOutput(&numericReq{}),
+        )
+}
+
+func TestNumericTypes(t *testing.T) {
+    doc := generate(t, numericService{})
+
+    props := doc.Definitions["numericReq"].Properties
+    for name, expected := range map[string][2]string{
+        "i8":    {"integer", "int8"},
+        "i16":   {"integer", "int16"},
+        "i":     {"integer", "int64"},
+        "u8":    {"integer", "uint8"},
+        "u16":   {"integer", "uint16"},
+        "u":     {"integer", "uint64"},
+        "f32":   {"number", "float"},
+        "bytes": {"string", "byte"},
+    } {
+        s := props[name]
+        if !s.Type.Contains(expected[0]) || s.Format != expected[1] {
+            t.Errorf("unexpected %s schema: %v %s", name, s.Type, s.Format)
+        }
+    }
+    for _, name := range []string{"u8", "u16", "u"} {
+        if s := props[name]; s.Minimum == nil || *s.Minimum != 0 {
+            t.Errorf("expected minimum 0 for %s", name)
+        }
+    }
+    if s := props["i8"]; s.Minimum != nil {
+        t.Errorf("unexpected minimum for i8")
+    }
+    if s := props["int8s"]; !s.Type.Contains("array") || s.Items.Schema.Format != "int8" {
+        t.Errorf("unexpected int8s schema: %v", s)
+    }
+}
+
+type queryReq struct {
+    ID     uint16      `json:"id"`
+    Names  []string    `json:"names"`
+    Labels []string    `json:"labels" swag:"collectionFormat:multi"`
+    Point  [2]float64  `json:"point"`
+    Flag   *bool       `json:"flag" swag:"optional"`
+    Any    interface{} `json:"any"`
+}
+
+type queryService struct{}
+
+func (queryService) Desc() *desc.Service {
+    return desc.NewService("queryService").
+        AddContract(
+            desc.NewContract().
+                AddSelector(fasthttp.GET("/query/:id")).
+                SetInput(&queryReq{}).
+                SetOutput(&nestedItem{}),
+        )
+}
+
+func TestQueryParams(t *testing.T) {
+    sg := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WithCollectionFormat("pipes")
+
+    doc := generateWith(t, sg, queryService{})
+
+    params := map[string]spec.Parameter{}
+    for _, p := range doc.Paths.Paths["/query/{id}"].Get.Parameters {
+        params[p.Name] = p
+    }
+    if _, ok := params["any"]; ok {
+        t.Errorf("unsupported param should not be added")
+    }
+    if p := params["id"]; p.In != "path" || p.Format != "uint16" || p.Minimum == nil {
+        t.Errorf("unexpected id param: %v", p)
+    }
+    if p := params["names"]; p.Type != "array" || p.Items.Type != "string" || p.CollectionFormat != "pipes" {
+        t.Errorf("unexpected names param: %v", p)
+    }
+    if p := params["labels"]; p.CollectionFormat != "multi" {
+        t.Errorf("unexpected labels param: %v", p)
+    }
+    if p := params["point"]; p.Items.Type != "number" || p.MaxItems == nil || *p.MaxItems != 2 {
+        t.Errorf("unexpected point param: %v", p)
+    }
+    if p := params["flag"]; p.Type != "boolean" || p.Required {
+        t.Errorf("unexpected flag param: %v", p)
+    }
+}
+
+type listFilter struct {
+    Status string     `json:"status" swag:"enum:active,closed"`
+    From   time.Time  `json:"from"`
+    To     *time.Time `json:"to" swag:"optional"`
+    Range  *dateRange `json:"range"`
+    Host   net.IP     `json:"host" swag:"optional"`
+}
+
+type dateRange struct {
+    Start string `json:"start"`
+}
+
+type listReq struct {
+    Filter listFilter        `json:"filter" swag:"optional"`
+    Labels map[string]string `json:"labels"`
+}
+
+type listService struct{}
+
+func (listService) Desc() *desc.Service {
+    return desc.NewService("listService").
+        AddContract(
+            desc.NewContract().
+                SetName("list").
+                AddSelector(fasthttp.GET("/list")).
+                SetInput(&listReq{}).
+                SetOutput(&nestedItem{}),
+        )
+}
+
+func TestStructQueryParams(t *testing.T) {
+    sg := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WithExample("listService", "list", &listReq{Filter: listFilter{Status: "closed"}}, nil)
+
+    doc := generateWith(t, sg, listService{})
+
+    params := map[string]spec.Parameter{}
+    for _, p := range doc.Paths.Paths["/list"].Get.Parameters {
+        params[p.Name] = p
+    }
+    for _, name := range []string{"filter.status", "filter.from", "filter.to", "filter.range.start", "filter.host"} {
+        p, ok := params[name]
+        if !ok {
+            t.Fatalf("expected %s param, got: %v", name, params)
+        }
+        if p.In != "query" || p.Required {
+            t.Errorf("unexpected %s param: %v", name, p)
+        }
+    }
+    if _, ok := params["filter"]; ok {
+        t.Errorf("struct param should be expanded")
+    }
+    if ex := params["filter.status"].Extensions["x-example"]; ex != "closed" {
+        t.Errorf("unexpected filter.status example: %v", ex)
+    }
+    // Swagger 2.0 has no object params, hence the map is only described by the operation.
+    if p, ok := params["labels"]; ok {
+        t.Errorf("unexpected labels param: %s %v", p.Type, p.Extensions)
+    }
+    note := doc.Paths.Paths["/list"].Get.Description
+    if !strings.Contains(note, "The labels query param is a map, which is sent as labels[key]=value") {
+        t.Errorf("unexpected description: %s", note)
+    }
+
+    // time.Time is marshaled as a string, hence it is neither expanded nor defined as an object.
+    for _, name := range []string{"filter.from", "filter.to"} {
+        if p := params[name]; p.Type != "string" || p.Format != "date-time" {
+            t.Errorf("unexpected %s param: %s %s", name, p.Type, p.Format)
+        }
+    }
+    if p := params["filter.host"]; p.Type != "string" || p.Format != "" {
+        t.Errorf("unexpected filter.host param: %s %s", p.Type, p.Format)
+    }
+    if _, ok := doc.Definitions["Time"]; ok {
+        t.Errorf("time.Time should not be defined: %v", doc.Definitions["Time"])
+    }
+    from := doc.Definitions["listFilter"].Properties["from"]
+    if from.Type[0] != "string" || from.Format != "date-time" {
+        t.Errorf("unexpected from property: %v %s", from.Type, from.Format)
+    }
+    if ex, ok := doc.Definitions["listFilter"].Example.(map[string]interface{}); !ok || ex["from"] != "2006-01-02T15:04:05Z" {
+        t.Errorf("unexpected listFilter example: %v", doc.Definitions["listFilter"].Example)
+    }
+}
+
+type fileReq struct {
+    Bucket   string `json:"bucket"`
+    FilePath string `json:"filepath"`
+}
+
+type pathService struct {
+    path string
+}
+
+func (s pathService) Desc() *desc.Service {
+    return desc.NewService("pathService").
+        AddContract(
+            desc.NewContract().
+                SetName("getFile").
+                AddSelector(fasthttp.GET(s.path)).
+                SetInput(&fileReq{}).
+                SetOutput(&nestedItem{}),
+        )
+}
+
+func TestPathParams(t *testing.T) {
+    doc := generate(t, pathService{path: "/files/:bucket/*filepath"})
+
+    pathItem, ok := doc.Paths.Paths["/files/{bucket}/{filepath}"]
+    if !ok {
+        t.Fatalf("expected wildcard path, got: %v", doc.Paths.Paths)
+    }
+    for _, p := range pathItem.Get.Parameters {
+        if p.In != "path" {
+            t.Errorf("expected %s to be a path param", p.Name)
+        }
+        if wildcard, _ := p.Extensions.GetBool("x-wildcard"); wildcard != (p.Name == "filepath") {
+            t.Errorf("unexpected x-wildcard of %s: %v", p.Name, wildcard)
+        }
+    }
+
+    // Fields are matched case-insensitively, and the params are named as in the route.
+    doc = generate(t, pathService{path: "/files/:Bucket/*FilePath"})
+    pathItem, ok = doc.Paths.Paths["/files/{Bucket}/{FilePath}"]
+    if !ok {
+        t.Fatalf("expected path with the route param names, got: %v", doc.Paths.Paths)
+    }
+    names := map[string]bool{}
+    for _, p := range pathItem.Get.Parameters {
+        names[p.Name] = p.In == "path"
+    }
+    if !names["Bucket"] || !names["FilePath"] || len(names) != 2 {
+        t.Errorf("expected Bucket and FilePath path params, got: %v", pathItem.Get.Parameters)
+    }
+
+    for path, expected := range map[string]string{
+        "/files/:id":       `path param "id" has no matching field in fileReq`,
+        "/files/*":         `path segment "*" must be named`,
+        "/files/:bucket/:": `path segment ":" must be named`,
+    } {
+        err := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+            WithTag("json").
+            WriteTo(&strings.Builder{}, pathService{path: path})
+        if err == nil || !strings.Contains(err.Error(), expected) {
+            t.Errorf("expected error %q for %s, got: %v", expected, path, err)
+        }
+    }
+}
+
+type fileRoutesService struct{}
+
+func (fileRoutesService) Desc() *desc.Service {
+    return desc.NewService("fileRoutesService").
+        AddContract(
+            desc.NewContract().
+                SetName("getFile").
+                AddSelector(fasthttp.GET("/files/:bucket/*filepath")).
+                AddSelector(fasthttp.GET("/buckets/:Bucket")).
+                SetInput(&fileReq{}).
+                SetOutput(&nestedItem{}),
+        )
+}
+
+func TestMultiRouteParams(t *testing.T) {
+    doc := generate(t, fileRoutesService{})
+
+    // Every route has its own params, i.e. the params of a route are not added to the others.
+    for path, expected := range map[string]map[string]string{
+        "/files/{bucket}/{filepath}": {"bucket": "path", "filepath": "path"},
+        "/buckets/{Bucket}":          {"Bucket": "path", "filepath": "query"},
+    } {
+        pathItem, ok := doc.Paths.Paths[path]
+        if !ok || pathItem.Get == nil {
+            t.Fatalf("expected %s, got: %v", path, doc.Paths.Paths)
+        }
+
+        params := map[string]string{}
+        for _, p := range pathItem.Get.Parameters {
+            params[p.Name] = p.In
+        }
+        if fmt.Sprint(params) != fmt.Sprint(expected) {
+            t.Errorf("unexpected params of %s: %v", path, params)
+        }
+    }
+
+    if id := doc.Paths.Paths["/files/{bucket}/{filepath}"].Get.ID; id != "getFile" {
+        t.Errorf("unexpected operation id of the first route: %s", id)
+    }
+    if id := doc.Paths.Paths["/buckets/{Bucket}"].Get.ID; id != "getFile2" {
+        t.Errorf("unexpected operation id of the second route: %s", id)
+    }
+}
+
+type taggedReq struct {
+    ID      string `msgpack:"id" path:"itemID"`
+    Search  string `msgpack:"search" query:"q"`
+    Token   string `msgpack:"-" header:"X-Token"`
+    Body    string `msgpack:"body"`
+    Count   int
+    Ignored string `msgpack:"-"`
+    hidden  string
+}
+
+type taggedService struct{}
+
+func (taggedService) Desc() *desc.Service {
+    return desc.NewService("taggedService").
+        AddContract(
+            desc.NewContract().
+                AddSelector(fasthttp.GET("/tagged/:itemID")).
+                SetInput(&taggedReq{}).
+                SetOutput(&nestedItem{}),
+        )
+}
+
+func TestTagKeys(t *testing.T) {
+    sg := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("msgpack").
+        WithQueryTag("query").
+        WithPathTag("path").
+        WithHeaderTag("header")
+
+    doc := generateWith(t, sg, taggedService{})
+
+    params := map[string]string{}
+    for _, p := range doc.Paths.Paths["/tagged/{itemID}"].Get.Parameters {
+        params[p.Name] = p.In
+    }
+    expected := map[string]string{"itemID": "path", "q": "query", "X-Token": "header"}
+    if fmt.Sprint(params) != fmt.Sprint(expected) {
+        t.Errorf("unexpected params: %v", params)
+    }
+
+    var props []string
+    for name := range doc.Definitions["taggedReq"].Properties {
+        props = append(props, name)
+    }
+    sort.Strings(props)
+    if strings.Join(props, ",") != "Count,body,id,search" {
+        t.Errorf("unexpected properties: %v", props)
+    }
+}
@@ -16,18 +16,21 @@ type parsedStructTag struct {
     Name           string
     Optional       bool
     PossibleValues []string
+    Example        string
+    // CollectionFormat is the format of the array params, e.g. csv, pipes or multi.
+    CollectionFormat string
+    // Extensions are the vendor extensions of the field, e.g. x-internal:true.
+    Extensions map[string]interface{}
 }
 
+// getParsedStructTag parses the swag tag, and names the field by the tag with the given key.
+// Name is empty if the field has no such tag or is tagged with "-".
 func getParsedStructTag(tag reflect.StructTag, name string) parsedStructTag {
     pst := parsedStructTag{}
     nameTag := tag.Get(name)
-    if nameTag == "" {
-        return pst
-    }
-
-    // This is a hack to remove omitempty from tags
-    fNameParts := strings.Split(nameTag, swagValueSep)
-    if len(fNameParts) > 0 {
+    if nameTag != "-" {
+        // This is a hack to remove omitempty from tags
+        fNameParts := strings.Split(nameTag, swagValueSep)
         pst.Name = strings.TrimSpace(fNameParts[0])
     }
 
@@ -46,8 +49,55 @@ func getParsedStructTag(tag reflect.StructTag, name string) parsedStructTag {
                     pst.PossibleValues = append(pst.PossibleValues, strings.TrimSpace(v))
                 }
             }
+        case strings.HasPrefix(x, "collectionformat:"):
+            xx := strings.SplitN(x, swagIdentSep, 2)
+            pst.CollectionFormat = strings.TrimSpace(xx[1])
+        case strings.HasPrefix(x, extensionPrefix):
+            xx := strings.SplitN(strings.TrimSpace(p), swagIdentSep, 2)
+            if len(xx) == 2 {
+                if pst.Extensions == nil {
+                    pst.Extensions = map[string]interface{}{}
+                }
+                pst.Extensions[xx[0]] = extensionValue(strings.TrimSpace(xx[1]))
+            }
+        case strings.HasPrefix(x, "example:"):
+            xx := strings.SplitN(p, swagIdentSep, 2)
+            if len(xx) == 2 {
+                pst.Example = strings.TrimSpace(xx[1])
+            }
         }
     }
 
     return pst
 }
+
+// parseField parses the tags of the field f, and names it by the tag with the given key. Like
+// encoding/json, exported fields without the tag are named by their Go name, and fields
+// tagged with "-" are skipped. It returns false if the field should be skipped.
+func parseField(f reflect.StructField, key string) (parsedStructTag, bool) {
+    if f.PkgPath != "" {
+        return parsedStructTag{}, false
+    }
+
+    pt := getParsedStructTag(f.Tag, key)
+    if pt.Name == "" {
+        if f.Tag.Get(key) == "-" {
+            return pt, false
+        }
+        pt.Name = f.Name
+    }
+
+    return pt, true
+}
+
+// lookupFieldName returns the name of the field f from the tag with the given key. Unlike
+// parseField, it does not fall back to the Go name of the field.
+func lookupFieldName(f reflect.StructField, key string) (string, bool) {
+    if f.PkgPath != "" || key == "" {
+        return "", false
+    }
+
+    name := getParsedStructTag(f.Tag, key).Name
+
+    return name, name != ""
+}
@@ -0,0 +1,275 @@
+package swagger
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "regexp"
+    "sort"
+    "strings"
+    "sync"
+
+    "github.com/go-openapi/spec"
+    "github.com/xeipuuv/gojsonschema"
+)
+
+const definitionsPrefix = "#/definitions/"
+
+var pathParamRegex = regexp.MustCompile(`{([^}]+)}`)
+
+// ValidationError aggregates all the problems which are found in the generated document.
+type ValidationError struct {
+    Problems []string
+}
+
+func (e *ValidationError) Error() string {
+    return fmt.Sprintf("invalid swagger document:\n  - %s", strings.Join(e.Problems, "\n  - "))
+}
+
+func (e *ValidationError) addf(format string, args ...interface{}) {
+    e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+// WithWarnings downgrades the validation errors to warnings, hence the document is written even
+// if it is invalid. The validation error is passed to f, if it is not nil.
+func (sg *swaggerGen) WithWarnings(f func(err error)) *swaggerGen {
+    sg.warnings = true
+    sg.onWarning = f
+
+    return sg
+}
+
+// swagger20Schema is the compiled Swagger 2.0 meta-schema, which is embedded in go-openapi/spec.
+var swagger20Schema = struct {
+    once   sync.Once
+    schema *gojsonschema.Schema
+    err    error
+}{}
+
+// validate checks the document against the Swagger 2.0 meta-schema, and the rules which the
+// meta-schema could not express: the integrity of the references, the path templates, the
+// unique operation ids and the body params. It returns nil if the document is valid.
+func validate(doc *spec.Swagger) *ValidationError {
+    v := &ValidationError{}
+    validateMetaSchema(v, doc)
+
+    names := make([]string, 0, len(doc.Definitions))
+    for name := range doc.Definitions {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        def := doc.Definitions[name]
+        validateSchema(v, doc, fmt.Sprintf("definitions.%s", name), &def)
+    }
+
+    if doc.Paths == nil {
+        return v.result()
+    }
+
+    paths := make([]string, 0, len(doc.Paths.Paths))
+    for path := range doc.Paths.Paths {
+        paths = append(paths, path)
+    }
+    sort.Strings(paths)
+
+    opIDs := map[string]string{}
+    for _, path := range paths {
+        pathItem := doc.Paths.Paths[path]
+        for _, mo := range []struct {
+            method string
+            op     *spec.Operation
+        }{
+            {http.MethodGet, pathItem.Get},
+            {http.MethodPost, pathItem.Post},
+            {http.MethodPut, pathItem.Put},
+            {http.MethodPatch, pathItem.Patch},
+            {http.MethodDelete, pathItem.Delete},
+        } {
+            if mo.op == nil {
+                continue
+            }
+
+            loc := fmt.Sprintf("%s %s", mo.method, path)
+            if mo.op.ID != "" {
+                if other, ok := opIDs[mo.op.ID]; ok {
+                    v.addf("%s: operationId %q is already used by %s", loc, mo.op.ID, other)
+                }
+                opIDs[mo.op.ID] = loc
+            }
+            validateOperation(v, doc, loc, path, mo.op)
+        }
+    }
+
+    return v.result()
+}
+
+// validateMetaSchema checks the JSON form of the document against the Swagger 2.0 meta-schema.
+func validateMetaSchema(v *ValidationError, doc *spec.Swagger) {
+    swagger20Schema.once.Do(func() {
+        raw, err := spec.Asset("v2/schema.json")
+        if err != nil {
+            swagger20Schema.err = err
+
+            return
+        }
+        swagger20Schema.schema, swagger20Schema.err = gojsonschema.NewSchema(
+            gojsonschema.NewBytesLoader(raw),
+        )
+    })
+    if swagger20Schema.err != nil {
+        v.addf("could not load the swagger 2.0 schema: %v", swagger20Schema.err)
+
+        return
+    }
+
+    docJSON, err := json.Marshal(doc)
+    if err != nil {
+        v.addf("could not marshal the document: %v", err)
+
+        return
+    }
+    res, err := swagger20Schema.schema.Validate(gojsonschema.NewBytesLoader(docJSON))
+    if err != nil {
+        v.addf("could not validate the document: %v", err)
+
+        return
+    }
+    for _, re := range res.Errors() {
+        // The failed branches of oneOf and anyOf are reported as separate errors too, which are
+        // only noise for the user.
+        switch re.Type() {
+        case "number_one_of", "number_any_of":
+            continue
+        }
+        problem := re.Description()
+        if !strings.HasPrefix(problem, re.Field()) {
+            problem = fmt.Sprintf("%s: %s", re.Field(), problem)
+        }
+        v.addf("%s", problem)
+    }
+}
+
+func (v *ValidationError) result() *ValidationError {
+    if len(v.Problems) == 0 {
+        return nil
+    }
+
+    return v
+}
+
+func validateOperation(v *ValidationError, doc *spec.Swagger, loc, path string, op *spec.Operation) {
+    templated := map[string]bool{}
+    for _, m := range pathParamRegex.FindAllStringSubmatch(path, -1) {
+        templated[m[1]] = true
+    }
+
+    seen := map[string]bool{}
+    bodies := 0
+    for _, p := range op.Parameters {
+        pLoc := fmt.Sprintf("%s: param %s (in %s)", loc, p.Name, p.In)
+        key := p.In + "/" + p.Name
+        if seen[key] {
+            v.addf("%s is duplicated", pLoc)
+        }
+        seen[key] = true
+
+        switch p.In {
+        case "body":
+            bodies++
+            if p.Schema == nil {
+                v.addf("%s has no schema", pLoc)
+            } else {
+                validateSchema(v, doc, pLoc, p.Schema)
+            }
+
+            continue
+        case "path":
+            if !templated[p.Name] {
+                v.addf("%s is not in the path", pLoc)
+            }
+            delete(templated, p.Name)
+        }
+
+        validateParamItems(v, pLoc, p.Type, p.Items)
+    }
+    if bodies > 1 {
+        v.addf("%s: only one body param is allowed", loc)
+    }
+    for name := range templated {
+        v.addf("%s: path param %s has no parameter", loc, name)
+    }
+
+    if op.Responses == nil {
+        return
+    }
+    codes := make([]int, 0, len(op.Responses.StatusCodeResponses))
+    for code := range op.Responses.StatusCodeResponses {
+        codes = append(codes, code)
+    }
+    sort.Ints(codes)
+    for _, code := range codes {
+        res := op.Responses.StatusCodeResponses[code]
+        if res.Schema != nil {
+            validateSchema(v, doc, fmt.Sprintf("%s: response %d", loc, code), res.Schema)
+        }
+    }
+}
+
+// validateParamItems checks that the array params have items, which the meta-schema does not
+// require.
+func validateParamItems(v *ValidationError, loc, typ string, items *spec.Items) {
+    if typ != "array" {
+        return
+    }
+    if items == nil {
+        v.addf("%s: array params must have items", loc)
+
+        return
+    }
+    validateParamItems(v, loc+" items", items.Type, items.Items)
+}
+
+// validateSchema checks that all the references in the schema s, and its sub-schemas, refer to
+// existing definitions.
+func validateSchema(v *ValidationError, doc *spec.Swagger, loc string, s *spec.Schema) {
+    if s == nil {
+        return
+    }
+
+    if ref := s.Ref.String(); ref != "" {
+        name := strings.TrimPrefix(ref, definitionsPrefix)
+        if _, ok := doc.Definitions[name]; !ok || name == ref {
+            v.addf("%s: $ref %s does not exist", loc, ref)
+        }
+    }
+
+    if _, ok := s.Extensions[oneOfExtension]; ok {
+        for idx, sub := range oneOfSchemas(s) {
+            sub := sub
+            validateSchema(v, doc, fmt.Sprintf("%s.%s[%d]", loc, oneOfExtension, idx), &sub)
+        }
+    }
+    if s.Items != nil {
+        validateSchema(v, doc, loc+".items", s.Items.Schema)
+        for idx := range s.Items.Schemas {
+            validateSchema(v, doc, fmt.Sprintf("%s.items[%d]", loc, idx), &s.Items.Schemas[idx])
+        }
+    }
+    if s.AdditionalProperties != nil {
+        validateSchema(v, doc, loc+".additionalProperties", s.AdditionalProperties.Schema)
+    }
+    for idx := range s.AllOf {
+        validateSchema(v, doc, fmt.Sprintf("%s.allOf[%d]", loc, idx), &s.AllOf[idx])
+    }
+
+    names := make([]string, 0, len(s.Properties))
+    for name := range s.Properties {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        ps := s.Properties[name]
+        validateSchema(v, doc, fmt.Sprintf("%s.%s", loc, name), &ps)
+    }
+}
@@ -0,0 +1,140 @@
+package swagger_test
+
+import (
+    "errors"
+    "strings"
+    "testing"
+
+    "github.com/clubpay/ronycontrib/swagger"
+    "github.com/clubpay/ronykit/desc"
+    "github.com/clubpay/ronykit/std/gateway/fasthttp"
+)
+
+type rawOutput []string
+
+type invalidService struct{}
+
+func (invalidService) Desc() *desc.Service {
+    return desc.NewService("invalidService").
+        AddContract(
+            desc.NewContract().
+                SetName("raw").
+                AddSelector(fasthttp.GET("/raw")).
+                SetInput(&nestedItem{}).
+                SetOutput(&rawOutput{}),
+        )
+}
+
+type anotherInvalidService struct{}
+
+func (anotherInvalidService) Desc() *desc.Service {
+    return desc.NewService("anotherInvalidService").
+        AddContract(
+            desc.NewContract().
+                SetName("raw").
+                AddSelector(fasthttp.GET("/another/raw")).
+                SetInput(&nestedItem{}).
+                SetOutput(&rawOutput{}),
+        )
+}
+
+func TestValidation(t *testing.T) {
+    err := swagger.NewSwagger("", "v0.0.1", "").
+        WithTag("json").
+        WriteTo(&strings.Builder{}, invalidService{}, anotherInvalidService{})
+
+    var verr *swagger.ValidationError
+    if !errors.As(err, &verr) {
+        t.Fatalf("expected validation error, got: %v", err)
+    }
+    if !strings.Contains(err.Error(), "info: title is required") || len(verr.Problems) != 1 {
+        t.Errorf("unexpected problems: %v", verr.Problems)
+    }
+
+    var warning error
+    sb := &strings.Builder{}
+    err = swagger.NewSwagger("", "v0.0.1", "").
+        WithTag("json").
+        WithWarnings(func(err error) { warning = err }).
+        WriteTo(sb, invalidService{}, anotherInvalidService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if warning == nil || sb.Len() == 0 {
+        t.Errorf("expected the document to be written with a warning")
+    }
+}
+
+func TestValidationMetaSchema(t *testing.T) {
+    err := swagger.NewSwagger("TestTitle", "v0.0.1", "").
+        WithTag("json").
+        WithCollectionFormat("commas").
+        WriteTo(&strings.Builder{}, queryService{})
+
+    // The collection format is not checked by the generator, but it is an enum of the meta-schema.
+    var verr *swagger.ValidationError
+    if !errors.As(err, &verr) {
+        t.Fatalf("expected validation error, got: %v", err)
+    }
+    if len(verr.Problems) != 2 {
+        t.Errorf("unexpected problems: %v", verr.Problems)
+    }
+    for _, problem := range verr.Problems {
+        if !strings.Contains(problem, "collectionFormat must be one of the following") {
+            t.Errorf("unexpected problem: %s", problem)
+        }
+    }
+}
+
+func TestValidationMultipleRoutes(t *testing.T) {
+    doc := generate(t, multiRouteService{})
+    get := doc.Paths.Paths["/items/{id}"].Get
+    post := doc.Paths.Paths["/items"].Post
+    if get.ID != "getItem" || post.ID != "getItem2" {
+        t.Errorf("unexpected operation ids: %s, %s", get.ID, post.ID)
+    }
+    for _, p := range post.Parameters {
+        if p.In == "path" {
+            t.Errorf("unexpected path param in POST /items: %v", p.Name)
+        }
+    }
+}
+
+func TestUniqueOperationIDs(t *testing.T) {
+    // The contracts of both services are named raw, so the second one is qualified.
+    doc := generate(t, invalidService{}, anotherInvalidService{})
+    raw := doc.Paths.Paths["/raw"].Get
+    anotherRaw := doc.Paths.Paths["/another/raw"].Get
+    if raw.ID != "raw" || anotherRaw.ID != "anotherInvalidService.raw" {
+        t.Errorf("unexpected operation ids: %s, %s", raw.ID, anotherRaw.ID)
+    }
+
+    // The clients and the Postman collection are generated from the same document.
+    goCode := &strings.Builder{}
+    cg := swagger.NewClientGen("client")
+    if err := cg.WriteGoTo(goCode, invalidService{}, anotherInvalidService{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := cg.WriteTSTo(&strings.Builder{}, invalidService{}, anotherInvalidService{}); err != nil {
+        t.Fatal(err)
+    }
+    err := swagger.NewPostman("TestTitle").WriteTo(&strings.Builder{}, invalidService{}, anotherInvalidService{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    vetGo(t, goCode.String())
+}
+
+type multiRouteService struct{}
+
+func (multiRouteService) Desc() *desc.Service {
+    return desc.NewService("multiRouteService").
+        AddContract(
+            desc.NewContract().
+                SetName("getItem").
+                AddSelector(fasthttp.GET("/items/:id")).
+                AddSelector(fasthttp.POST("/items")).
+                SetInput(&nestedItem{}).
+                SetOutput(&nestedItem{}),
+        )
+}